@@ -0,0 +1,170 @@
+package buffer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BCDFormat selects how decimal digits are encoded by the BCD accessors.
+//
+// A BCD code is laid out as an integer of BCDBitSize bits whose nibbles (or
+// bytes, for unpacked BCD) are the digits, so it follows the bit order of the
+// buffer: MSBFirst buffers hold the most significant digit first and LSBFirst
+// buffers the least significant one, which gives the little endian byte order
+// used by many meters.
+type BCDFormat int
+
+const (
+	// BCDPacked stores one digit per nibble.
+	BCDPacked BCDFormat = iota
+	// BCDUnpacked stores one digit per byte, with the upper nibble cleared.
+	BCDUnpacked
+	// BCDPackedDecimal is the IBM packed decimal format: packed digits
+	// followed by a sign nibble (0xC positive, 0xD negative). 0xA, 0xE and
+	// 0xF are also read as positive and 0xB as negative.
+	BCDPackedDecimal
+)
+
+func (b BCDFormat) String() string {
+	switch b {
+	case BCDPacked:
+		return "packed BCD"
+	case BCDUnpacked:
+		return "unpacked BCD"
+	case BCDPackedDecimal:
+		return "packed decimal"
+	default:
+		return fmt.Sprintf("BCDFormat(%d)", int(b))
+	}
+}
+
+// BCDBitSize returns the number of bits taken by digits digits.
+func BCDBitSize(format BCDFormat, digits int) int {
+	switch format {
+	case BCDUnpacked:
+		return digits * 8
+	case BCDPackedDecimal:
+		return (digits + 1) * 4
+	default:
+		return digits * 4
+	}
+}
+
+// SetBCDFromString stores the decimal number s using digits digits. s may be
+// shorter than digits (it is padded with zeros) and may start with a sign,
+// although '-' is only accepted by BCDPackedDecimal.
+func (f *Buffer) SetBCDFromString(reqidx int, s string, digits int, format BCDFormat) (err error) {
+	var idx int
+	if idx, err = f.parseBCDParams(reqidx, digits, format); err != nil {
+		return err
+	}
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if negative && format != BCDPackedDecimal {
+		return fmt.Errorf("negative values can't be stored as %v", format)
+	}
+	if len(s) > digits {
+		return fmt.Errorf("value %q doesn't fit in %d BCD digits", s, digits)
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("invalid decimal number %q", s)
+		}
+	}
+	s = strings.Repeat("0", digits-len(s)) + s
+	units := digits
+	width := 4
+	switch format {
+	case BCDUnpacked:
+		width = 8
+	case BCDPackedDecimal:
+		units++
+	}
+	for i := 0; i < digits; i++ {
+		writeBits(f.buffer, f.order, f.bcdPos(idx, i, units, width), width, uint64(s[i]-'0'))
+	}
+	if format == BCDPackedDecimal {
+		sign := uint64(0xc)
+		if negative {
+			sign = 0xd
+		}
+		writeBits(f.buffer, f.order, f.bcdPos(idx, digits, units, width), width, sign)
+	}
+	return nil
+}
+
+// GetBCDToString returns the digits digits stored at idx, zero padded and
+// preceded by '-' for negative packed decimal values. Invalid digits and
+// signs are reported as errors.
+func (f *Buffer) GetBCDToString(reqidx int, digits int, format BCDFormat) (s string, err error) {
+	var idx int
+	if idx, err = f.parseBCDParams(reqidx, digits, format); err != nil {
+		return "", err
+	}
+	units := digits
+	width := 4
+	switch format {
+	case BCDUnpacked:
+		width = 8
+	case BCDPackedDecimal:
+		units++
+	}
+	out := make([]byte, 0, digits+1)
+	if format == BCDPackedDecimal {
+		pos := f.bcdPos(idx, digits, units, width)
+		switch sign := readBits(f.buffer, f.order, pos, width); sign {
+		case 0xa, 0xc, 0xe, 0xf:
+		case 0xb, 0xd:
+			out = append(out, '-')
+		default:
+			return "", fmt.Errorf("invalid packed decimal sign 0x%x at bit %d", sign, pos-f.offset)
+		}
+	}
+	for i := 0; i < digits; i++ {
+		pos := f.bcdPos(idx, i, units, width)
+		d := readBits(f.buffer, f.order, pos, width)
+		if d > 9 {
+			return "", fmt.Errorf("invalid BCD digit 0x%x at bit %d", d, pos-f.offset)
+		}
+		out = append(out, byte('0'+d))
+	}
+	return string(out), nil
+}
+
+func (f *Buffer) SetBCDFromInt64(idx int, v int64, digits int, format BCDFormat) error {
+	return f.SetBCDFromString(idx, strconv.FormatInt(v, 10), digits, format)
+}
+
+func (f *Buffer) GetBCDToInt64(idx int, digits int, format BCDFormat) (int64, error) {
+	s, err := f.GetBCDToString(idx, digits, format)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func (f *Buffer) parseBCDParams(reqidx int, digits int, format BCDFormat) (int, error) {
+	if format < BCDPacked || format > BCDPackedDecimal {
+		return 0, fmt.Errorf("invalid BCD format (%d)", int(format))
+	}
+	if digits <= 0 {
+		return 0, fmt.Errorf("invalid number of BCD digits (%d)", digits)
+	}
+	return f.parseParams(reqidx, BCDBitSize(format, digits), -1)
+}
+
+// bcdPos returns the absolute position of unit i (0 being the most
+// significant) of a code made of units units of width bits.
+func (f *Buffer) bcdPos(idx int, i int, units int, width int) int {
+	if f.order == LSBFirst {
+		i = units - 1 - i
+	}
+	return f.offset + idx + i*width
+}
@@ -0,0 +1,107 @@
+package buffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BCD_ByteLayout(t *testing.T) {
+	cases := []struct {
+		order  BitOrder
+		format BCDFormat
+		s      string
+		digits int
+		raw    []byte
+	}{
+		{MSBFirst, BCDPacked, "1234", 4, []byte{0x12, 0x34}},
+		{LSBFirst, BCDPacked, "1234", 4, []byte{0x34, 0x12}},
+		{MSBFirst, BCDPacked, "59", 4, []byte{0x00, 0x59}},
+		{MSBFirst, BCDUnpacked, "907", 3, []byte{0x09, 0x00, 0x07}},
+		{LSBFirst, BCDUnpacked, "907", 3, []byte{0x07, 0x00, 0x09}},
+		{MSBFirst, BCDPackedDecimal, "123", 3, []byte{0x12, 0x3c}},
+		{MSBFirst, BCDPackedDecimal, "-123", 3, []byte{0x12, 0x3d}},
+		{MSBFirst, BCDPackedDecimal, "+42", 5, []byte{0x00, 0x04, 0x2c}},
+		{LSBFirst, BCDPackedDecimal, "-123", 3, []byte{0x3d, 0x12}},
+	}
+	for _, c := range cases {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(BCDBitSize(c.format, c.digits), c.order)
+		require.Nil(t, buf.SetBCDFromString(0, c.s, c.digits, c.format))
+		require.Equal(t, c.raw, buf.GetRawBuffer(), "%v %v %q", c.order, c.format, c.s)
+	}
+}
+
+func Test_BCD_UnalignedRoundTrip(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for _, format := range []BCDFormat{BCDPacked, BCDUnpacked, BCDPackedDecimal} {
+			for idx := 0; idx < 8; idx++ {
+				buf := &Buffer{}
+				buf.InitWithBitOrder(idx+BCDBitSize(format, 20)+3, order)
+				buf.Not()
+				require.Nil(t, buf.SetBCDFromString(idx, "01234567890123456789", 20, format))
+				s, err := buf.GetBCDToString(idx, 20, format)
+				require.Nil(t, err)
+				require.Equal(t, "01234567890123456789", s)
+				// surrounding bits are untouched
+				if idx > 0 {
+					n, _ := buf.OnesCount(0, idx)
+					require.Equal(t, idx, n)
+				}
+				n, _ := buf.OnesCount(-1, 3)
+				require.Equal(t, 3, n)
+			}
+		}
+	}
+}
+
+func Test_BCD_Int64(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(100)
+	require.Nil(t, buf.SetBCDFromInt64(3, -9223372036854775808, 19, BCDPackedDecimal))
+	v, err := buf.GetBCDToInt64(3, 19, BCDPackedDecimal)
+	require.Nil(t, err)
+	require.Equal(t, int64(-9223372036854775808), v)
+
+	require.Nil(t, buf.SetBCDFromInt64(-1, 86400, 6, BCDPacked))
+	v, err = buf.GetBCDToInt64(-1, 6, BCDPacked)
+	require.Nil(t, err)
+	require.Equal(t, int64(86400), v)
+	s, err := buf.GetBCDToString(-1, 6, BCDPacked)
+	require.Nil(t, err)
+	require.Equal(t, "086400", s)
+
+	require.NotNil(t, buf.SetBCDFromInt64(0, -1, 4, BCDPacked))
+	require.NotNil(t, buf.SetBCDFromInt64(0, 12345, 4, BCDPacked))
+}
+
+func Test_BCD_InvalidInput(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(16)
+	require.NotNil(t, buf.SetBCDFromString(0, "12a4", 4, BCDPacked))
+	require.NotNil(t, buf.SetBCDFromString(0, "-12", 4, BCDUnpacked))
+	require.NotNil(t, buf.SetBCDFromString(0, "12", 0, BCDPacked))
+	require.NotNil(t, buf.SetBCDFromString(0, "12", 2, BCDFormat(7)))
+	require.NotNil(t, buf.SetBCDFromString(1, "1234", 4, BCDPacked))
+
+	require.Nil(t, buf.SetBitsFromUint64(0, 0x12a4, 16))
+	_, err := buf.GetBCDToString(0, 4, BCDPacked)
+	require.EqualError(t, err, "invalid BCD digit 0xa at bit 8")
+
+	require.Nil(t, buf.SetBitsFromUint64(0, 0x0103, 16))
+	_, err = buf.GetBCDToString(0, 2, BCDUnpacked)
+	require.Nil(t, err)
+	require.Nil(t, buf.SetBitsFromUint64(0, 0x3103, 16))
+	_, err = buf.GetBCDToString(0, 2, BCDUnpacked)
+	require.EqualError(t, err, "invalid BCD digit 0x31 at bit 0")
+
+	for sign, want := range map[uint64]string{0xa: "123", 0xb: "-123", 0xe: "123", 0xf: "123"} {
+		require.Nil(t, buf.SetBitsFromUint64(0, 0x1230|sign, 16))
+		s, err := buf.GetBCDToString(0, 3, BCDPackedDecimal)
+		require.Nil(t, err)
+		require.Equal(t, want, s)
+	}
+	require.Nil(t, buf.SetBitsFromUint64(0, 0x1239, 16))
+	_, err = buf.GetBCDToString(0, 3, BCDPackedDecimal)
+	require.EqualError(t, err, "invalid packed decimal sign 0x9 at bit 12")
+}
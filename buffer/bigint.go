@@ -0,0 +1,120 @@
+package buffer
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Big integer accessors work as their 64-bit counterparts for any size. The
+// value is laid out as SetBitsFromUint64 does: most significant bit first in
+// MSBFirst buffers and least significant bit first in LSBFirst buffers.
+
+// SetBitsFromBigInt stores the size low bits of the two's complement of v, so
+// it works for signed and unsigned values.
+func (f *Buffer) SetBitsFromBigInt(reqidx int, v *big.Int, size int) (err error) {
+	if v == nil || size < 0 {
+		return fmt.Errorf("invalid parameters")
+	}
+	var idx int
+	if idx, err = f.parseParams(reqidx, size, -1); err != nil {
+		return err
+	}
+	// And works on the infinite two's complement of negative values
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(size))
+	mask.Sub(mask, big.NewInt(1))
+	u := new(big.Int).And(v, mask)
+	chunk := new(big.Int)
+	word := new(big.Int).SetUint64(^uint64(0))
+	for pos := 0; pos < size; pos += 64 {
+		take := 64
+		if take > size-pos {
+			take = size - pos
+		}
+		// shift of the chunk inside the value
+		shift := pos
+		if f.order == MSBFirst {
+			shift = size - pos - take
+		}
+		chunk.Rsh(u, uint(shift)).And(chunk, word)
+		writeBits(f.buffer, f.order, f.offset+idx+pos, take, chunk.Uint64())
+	}
+	return nil
+}
+
+// GetBitsToBigUint returns the size bits at idx as an unsigned value.
+func (f *Buffer) GetBitsToBigUint(reqidx int, size int) (*big.Int, error) {
+	idx, err := f.parseParams(reqidx, size, -1)
+	if err != nil {
+		return nil, err
+	}
+	v := new(big.Int)
+	chunk := new(big.Int)
+	for pos := 0; pos < size; pos += 64 {
+		take := 64
+		if take > size-pos {
+			take = size - pos
+		}
+		chunk.SetUint64(readBits(f.buffer, f.order, f.offset+idx+pos, take))
+		if f.order == MSBFirst {
+			v.Lsh(v, uint(take)).Or(v, chunk)
+		} else {
+			v.Or(v, chunk.Lsh(chunk, uint(pos)))
+		}
+	}
+	return v, nil
+}
+
+// GetBitsToBigInt returns the size bits at idx as a two's complement value.
+func (f *Buffer) GetBitsToBigInt(reqidx int, size int) (*big.Int, error) {
+	v, err := f.GetBitsToBigUint(reqidx, size)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 && v.Bit(size-1) != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(size)))
+	}
+	return v, nil
+}
+
+// FitBigInt works as FitInt64 (signed) or FitUint64 for any size. The
+// returned value is never v itself.
+func FitBigInt(v *big.Int, size int, signed bool, mode OverflowMode) (*big.Int, error) {
+	min, max := bigRange(size, signed)
+	switch {
+	case mode == OverflowTruncate, v.Cmp(min) >= 0 && v.Cmp(max) <= 0:
+		return new(big.Int).Set(v), nil
+	case mode == OverflowCheck:
+		return nil, &OverflowError{Value: v, Size: size, Signed: signed}
+	case v.Sign() < 0:
+		return min, nil
+	default:
+		return max, nil
+	}
+}
+
+// SetBitsFromBigIntWithOverflow works as SetBitsFromBigInt, handling values
+// out of the range of a size bits integer as mode says.
+func (f *Buffer) SetBitsFromBigIntWithOverflow(idx int, v *big.Int, size int, signed bool, mode OverflowMode) (err error) {
+	if v == nil {
+		return fmt.Errorf("invalid parameters")
+	}
+	if v, err = FitBigInt(v, size, signed, mode); err != nil {
+		return err
+	}
+	return f.SetBitsFromBigInt(idx, v, size)
+}
+
+// bigRange returns the range of a size bits integer.
+func bigRange(size int, signed bool) (min *big.Int, max *big.Int) {
+	if size <= 0 {
+		return new(big.Int), new(big.Int)
+	}
+	if signed {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+		min = new(big.Int).Neg(max)
+	} else {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(size))
+		min = new(big.Int)
+	}
+	return min, max.Sub(max, big.NewInt(1))
+}
@@ -0,0 +1,114 @@
+package buffer
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BigInt_Layout(t *testing.T) {
+	v, _ := new(big.Int).SetString("0x0102030405060708090a0b0c", 0)
+	buf := &Buffer{}
+	buf.Init(96)
+	require.Nil(t, buf.SetBitsFromBigInt(0, v, 96))
+	require.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, buf.GetRawBuffer())
+	out, err := buf.GetBitsToBigUint(0, 96)
+	require.Nil(t, err)
+	require.Equal(t, 0, v.Cmp(out))
+
+	buf.InitWithBitOrder(96, LSBFirst)
+	require.Nil(t, buf.SetBitsFromBigInt(0, v, 96))
+	require.Equal(t, []byte{12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, buf.GetRawBuffer())
+	out, err = buf.GetBitsToBigUint(0, 96)
+	require.Nil(t, err)
+	require.Equal(t, 0, v.Cmp(out))
+}
+
+func Test_BigInt_MatchesUint64(t *testing.T) {
+	rnd := rand.New(rand.NewSource(23))
+	for i := 0; i < 500; i++ {
+		order := BitOrder(i % 2)
+		size := 1 + rnd.Intn(64)
+		idx := rnd.Intn(20)
+		v := rnd.Uint64()
+		a := &Buffer{}
+		a.InitWithBitOrder(100, order)
+		b := &Buffer{}
+		b.InitWithBitOrder(100, order)
+		require.Nil(t, a.SetBitsFromUint64(idx, v, size))
+		require.Nil(t, b.SetBitsFromBigInt(idx, new(big.Int).SetUint64(v), size))
+		require.Equal(t, a.GetRawBuffer(), b.GetRawBuffer())
+
+		u, _ := a.GetBitsToUint64(idx, size)
+		bu, err := b.GetBitsToBigUint(idx, size)
+		require.Nil(t, err)
+		require.Equal(t, u, bu.Uint64())
+		s, _ := a.GetBitsToInt64(idx, size)
+		bs, err := b.GetBitsToBigInt(idx, size)
+		require.Nil(t, err)
+		require.Equal(t, s, bs.Int64())
+	}
+}
+
+func Test_BigInt_Signed(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for _, size := range []int{65, 100, 128, 131} {
+			buf := &Buffer{}
+			buf.InitWithBitOrder(size+7, order)
+			min := new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+			min.Neg(min)
+			max := new(big.Int).Not(min)
+			for _, v := range []*big.Int{min, max, big.NewInt(-1), big.NewInt(0), big.NewInt(-12345)} {
+				require.Nil(t, buf.SetBitsFromBigInt(-1, v, size))
+				out, err := buf.GetBitsToBigInt(-1, size)
+				require.Nil(t, err)
+				require.Equal(t, 0, v.Cmp(out), "%v %d %v", order, size, v)
+			}
+			// -1 sets every bit
+			require.Nil(t, buf.SetBitsFromBigInt(-1, big.NewInt(-1), size))
+			n, _ := buf.OnesCount(-1, size)
+			require.Equal(t, size, n)
+			u, _ := buf.GetBitsToBigUint(-1, size)
+			require.Equal(t, size, u.BitLen())
+		}
+	}
+}
+
+func Test_BigInt_Errors(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(100)
+	require.NotNil(t, buf.SetBitsFromBigInt(0, big.NewInt(1), 101))
+	require.NotNil(t, buf.SetBitsFromBigInt(0, nil, 8))
+	require.NotNil(t, buf.SetBitsFromBigInt(0, big.NewInt(1), -1))
+	_, err := buf.GetBitsToBigInt(50, 51)
+	require.NotNil(t, err)
+}
+
+func Test_BigInt_Overflow(t *testing.T) {
+	v := new(big.Int).Lsh(big.NewInt(1), 100)
+	buf := &Buffer{}
+	buf.Init(100)
+	err := buf.SetBitsFromBigIntWithOverflow(0, v, 100, false, OverflowCheck)
+	require.EqualError(t, err, "value 1267650600228229401496703205376 doesn't fit in 100 bits (min: 0  max: 1267650600228229401496703205375)")
+	require.Nil(t, buf.SetBitsFromBigIntWithOverflow(0, v, 100, false, OverflowSaturate))
+	n, _ := buf.OnesCount(0, 100)
+	require.Equal(t, 100, n)
+	require.Nil(t, buf.SetBitsFromBigIntWithOverflow(0, v, 100, false, OverflowTruncate))
+	n, _ = buf.OnesCount(0, 100)
+	require.Equal(t, 0, n)
+
+	require.Nil(t, buf.SetBitsFromBigIntWithOverflow(0, v, 100, true, OverflowSaturate))
+	out, _ := buf.GetBitsToBigInt(0, 100)
+	require.Equal(t, 0, out.Cmp(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 99), big.NewInt(1))))
+	require.Nil(t, buf.SetBitsFromBigIntWithOverflow(0, v.Neg(v), 100, true, OverflowSaturate))
+	out, _ = buf.GetBitsToBigInt(0, 100)
+	require.Equal(t, 0, out.Cmp(new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 99))))
+
+	fitted, err := FitBigInt(big.NewInt(-8), 4, true, OverflowCheck)
+	require.Nil(t, err)
+	require.Equal(t, int64(-8), fitted.Int64())
+	_, err = FitBigInt(big.NewInt(-1), 4, false, OverflowCheck)
+	require.EqualError(t, err, "value -1 doesn't fit in 4 bits (min: 0  max: 15)")
+}
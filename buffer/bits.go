@@ -0,0 +1,145 @@
+package buffer
+
+// Low level helpers working on whole bytes of a raw slice. Positions are
+// absolute bit positions inside the slice, numbered following order.
+// Callers are responsible for bounds checking.
+
+// lowMask returns a byte with the n (0..8) least significant bits set.
+func lowMask(n int) byte {
+	return byte(uint(0xff) >> (8 - n))
+}
+
+// readBits returns n (0..64) bits starting at pos. The first bit read is the
+// most significant of the result when order is MSBFirst and the least
+// significant when order is LSBFirst.
+func readBits(buf []byte, order BitOrder, pos int, n int) (v uint64) {
+	shift := 0
+	for n > 0 {
+		bytePos := pos >> 3
+		bitOff := pos & 7
+		take := 8 - bitOff
+		if take > n {
+			take = n
+		}
+		b := buf[bytePos]
+		if order == LSBFirst {
+			v |= uint64((b>>bitOff)&lowMask(take)) << shift
+			shift += take
+		} else {
+			v = v<<take | uint64((b>>(8-bitOff-take))&lowMask(take))
+		}
+		pos += take
+		n -= take
+	}
+	return v
+}
+
+// writeBits stores the n (0..64) low bits of v starting at pos, using the
+// same bit layout as readBits.
+func writeBits(buf []byte, order BitOrder, pos int, n int, v uint64) {
+	for n > 0 {
+		bytePos := pos >> 3
+		bitOff := pos & 7
+		take := 8 - bitOff
+		if take > n {
+			take = n
+		}
+		mask := lowMask(take)
+		if order == LSBFirst {
+			chunk := byte(v) & mask
+			v >>= take
+			buf[bytePos] = buf[bytePos]&^(mask<<bitOff) | chunk<<bitOff
+		} else {
+			shift := 8 - bitOff - take
+			chunk := byte(v>>(n-take)) & mask
+			buf[bytePos] = buf[bytePos]&^(mask<<shift) | chunk<<shift
+		}
+		pos += take
+		n -= take
+	}
+}
+
+// copyBits copies n bits from src (starting at srcPos) to dst (starting at
+// dstPos). Both slices must use the same bit order and must not overlap.
+func copyBits(dst []byte, dstPos int, src []byte, srcPos int, n int, order BitOrder) {
+	if dstPos&7 == 0 && srcPos&7 == 0 {
+		numBytes := n >> 3
+		copy(dst[dstPos>>3:dstPos>>3+numBytes], src[srcPos>>3:srcPos>>3+numBytes])
+		dstPos += numBytes << 3
+		srcPos += numBytes << 3
+		n -= numBytes << 3
+	}
+	for n > 0 {
+		take := 64
+		if take > n {
+			take = n
+		}
+		writeBits(dst, order, dstPos, take, readBits(src, order, srcPos, take))
+		dstPos += take
+		srcPos += take
+		n -= take
+	}
+}
+
+// fillBits sets n bits starting at pos to v.
+func fillBits(buf []byte, order BitOrder, pos int, n int, v bool) {
+	var word uint64
+	if v {
+		word = 0xffffffffffffffff
+	}
+	if pos&7 == 0 && n >= 8 {
+		numBytes := n >> 3
+		for i := pos >> 3; i < pos>>3+numBytes; i++ {
+			buf[i] = byte(word)
+		}
+		pos += numBytes << 3
+		n -= numBytes << 3
+	}
+	for n > 0 {
+		take := 64
+		if take > n {
+			take = n
+		}
+		writeBits(buf, order, pos, take, word)
+		pos += take
+		n -= take
+	}
+}
+
+// moveBits copies n bits from srcPos to dstPos inside the same slice. The
+// ranges may overlap.
+func moveBits(buf []byte, order BitOrder, dstPos int, srcPos int, n int) {
+	if dstPos == srcPos || n <= 0 {
+		return
+	}
+	if dstPos&7 == 0 && srcPos&7 == 0 {
+		numBytes := n >> 3
+		tail := n - numBytes<<3
+		tailValue := readBits(buf, order, srcPos+numBytes<<3, tail)
+		// copy has memmove semantics
+		copy(buf[dstPos>>3:dstPos>>3+numBytes], buf[srcPos>>3:srcPos>>3+numBytes])
+		writeBits(buf, order, dstPos+numBytes<<3, tail, tailValue)
+		return
+	}
+	if dstPos < srcPos {
+		for n > 0 {
+			take := 64
+			if take > n {
+				take = n
+			}
+			writeBits(buf, order, dstPos, take, readBits(buf, order, srcPos, take))
+			dstPos += take
+			srcPos += take
+			n -= take
+		}
+		return
+	}
+	for n > 0 {
+		take := 64
+		if take > n {
+			take = n
+		}
+		n -= take
+		writeBits(buf, order, dstPos+n, take, readBits(buf, order, srcPos+n, take))
+	}
+}
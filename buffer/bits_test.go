@@ -0,0 +1,161 @@
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Per-bit reference implementations, kept to check the word based accessors
+// and to benchmark against them.
+
+func refSetBitsFromUint64(f *Buffer, idx int, v uint64, size int) {
+	for bit := 0; bit < size; bit++ {
+		i := idx + size - 1 - bit
+		if f.order == LSBFirst {
+			i = idx + bit
+		}
+		f.SetBit(i, (v&(uint64(1)<<bit)) != 0)
+	}
+}
+
+func refGetBitsToUint64(f *Buffer, idx int, size int) uint64 {
+	var v uint64
+	for bit := 0; bit < size; bit++ {
+		i := idx + size - 1 - bit
+		if f.order == LSBFirst {
+			i = idx + bit
+		}
+		if bitValue, _ := f.GetBit(i); bitValue {
+			v |= uint64(1) << bit
+		}
+	}
+	return v
+}
+
+func refSetBitsFromRawBuffer(f *Buffer, idx int, b []byte, size int) {
+	for i := 0; i < size; i++ {
+		f.SetBit(idx+i, (b[i/8]&f.bitMask(i%8)) != 0)
+	}
+}
+
+func refGetBitsToRawBuffer(f *Buffer, idx int, size int) []byte {
+	res := make([]byte, getByteSize(size))
+	for i := 0; i < size; i++ {
+		if bitValue, _ := f.GetBit(idx + i); bitValue {
+			res[i/8] |= f.bitMask(i % 8)
+		}
+	}
+	return res
+}
+
+func randomBuffer(rnd *rand.Rand, numBytes int, order BitOrder) *Buffer {
+	raw := make([]byte, numBytes)
+	rnd.Read(raw)
+	buf := &Buffer{}
+	buf.InitFromRawBufferWithBitOrder(raw, order)
+	return buf
+}
+
+func Test_WordAccessors_MatchPerBit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for iter := 0; iter < 2000; iter++ {
+			buf := randomBuffer(rnd, 16, order)
+			size := rnd.Intn(65)
+			idx := rnd.Intn(buf.GetBitSize() - size + 1)
+
+			u, err := buf.GetBitsToUint64(idx, size)
+			require.Nil(t, err)
+			require.Equal(t, refGetBitsToUint64(buf, idx, size), u)
+
+			i, err := buf.GetBitsToInt64(idx, size)
+			require.Nil(t, err)
+			ref := refGetBitsToUint64(buf, idx, size)
+			if size > 0 && size < 64 && ref&(uint64(1)<<(size-1)) != 0 {
+				ref |= 0xffffffffffffffff << size
+			}
+			require.Equal(t, int64(ref), i)
+
+			v := rnd.Uint64()
+			a := buf.GetCopy()
+			b := buf.GetCopy()
+			require.Nil(t, a.SetBitsFromUint64(idx, v, size))
+			refSetBitsFromUint64(b, idx, v, size)
+			require.Equal(t, b.GetRawBuffer(), a.GetRawBuffer())
+
+			rawSize := rnd.Intn(buf.GetBitSize() + 1)
+			rawIdx := rnd.Intn(buf.GetBitSize() - rawSize + 1)
+			raw, err := buf.GetBitsToRawBuffer(rawIdx, rawSize)
+			require.Nil(t, err)
+			require.Equal(t, refGetBitsToRawBuffer(buf, rawIdx, rawSize), raw)
+
+			src := make([]byte, getByteSize(rawSize))
+			rnd.Read(src)
+			a = buf.GetCopy()
+			b = buf.GetCopy()
+			require.Nil(t, a.SetBitsFromRawBuffer(rawIdx, src, rawSize))
+			refSetBitsFromRawBuffer(b, rawIdx, src, rawSize)
+			require.Equal(t, b.GetRawBuffer(), a.GetRawBuffer())
+		}
+	}
+}
+
+func Benchmark_GetBitsToUint64(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 64, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		buf.GetBitsToUint64(i%200, 37)
+	}
+}
+
+func Benchmark_GetBitsToUint64_PerBit(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 64, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		refGetBitsToUint64(buf, i%200, 37)
+	}
+}
+
+func Benchmark_SetBitsFromUint64(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 64, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		buf.SetBitsFromUint64(i%200, uint64(i), 37)
+	}
+}
+
+func Benchmark_SetBitsFromUint64_PerBit(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 64, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		refSetBitsFromUint64(buf, i%200, uint64(i), 37)
+	}
+}
+
+func Benchmark_GetBitsToRawBuffer(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 256, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		buf.GetBitsToRawBuffer(3, 1500)
+	}
+}
+
+func Benchmark_GetBitsToRawBuffer_PerBit(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 256, MSBFirst)
+	for i := 0; i < b.N; i++ {
+		refGetBitsToRawBuffer(buf, 3, 1500)
+	}
+}
+
+func Benchmark_SetBitsFromRawBuffer(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 256, MSBFirst)
+	src := make([]byte, 188)
+	for i := 0; i < b.N; i++ {
+		buf.SetBitsFromRawBuffer(3, src, 1500)
+	}
+}
+
+func Benchmark_SetBitsFromRawBuffer_PerBit(b *testing.B) {
+	buf := randomBuffer(rand.New(rand.NewSource(1)), 256, MSBFirst)
+	src := make([]byte, 188)
+	for i := 0; i < b.N; i++ {
+		refSetBitsFromRawBuffer(buf, 3, src, 1500)
+	}
+}
@@ -4,49 +4,136 @@ import (
 	"fmt"
 )
 
+// BitOrder selects how bit indexes are mapped to the bits of each byte.
+type BitOrder int
+
+const (
+	// MSBFirst numbers bit 0 as the most significant bit of the first byte
+	// (0x80). Multi-bit values are stored with their most significant bit
+	// at the lowest index.
+	MSBFirst BitOrder = iota
+	// LSBFirst numbers bit 0 as the least significant bit of the first byte
+	// (0x01). Multi-bit values are stored with their least significant bit
+	// at the lowest index (Intel ordering).
+	LSBFirst
+)
+
+func (o BitOrder) String() string {
+	switch o {
+	case MSBFirst:
+		return "MSBFirst"
+	case LSBFirst:
+		return "LSBFirst"
+	default:
+		return fmt.Sprintf("BitOrder(%d)", int(o))
+	}
+}
+
 type Buffer struct {
 	bitSize int
 	buffer  []byte
+	order   BitOrder
+	// offset is the position in buffer of the first bit. It is advanced by
+	// Read and reset to 0 when the buffer is compacted.
+	offset int
+	// view is set when buffer is shared with the Buffer this one was sliced
+	// from. Views never move or reallocate the shared storage.
+	view bool
 }
 
 func (f *Buffer) Init(bitSize int) {
+	f.InitWithBitOrder(bitSize, MSBFirst)
+}
+
+func (f *Buffer) InitWithBitOrder(bitSize int, order BitOrder) {
 	f.bitSize = bitSize
 	byteSize := getByteSize(bitSize)
 	f.buffer = make([]byte, byteSize)
+	f.order = order
+	f.offset = 0
+	f.view = false
 }
 
 func (f *Buffer) InitFromRawBuffer(buff []byte) {
+	f.InitFromRawBufferWithBitOrder(buff, MSBFirst)
+}
+
+func (f *Buffer) InitFromRawBufferWithBitOrder(buff []byte, order BitOrder) {
 	f.bitSize = len(buff) * 8
 	f.buffer = buff
+	f.order = order
+	f.offset = 0
+	f.view = false
 }
 
 func (f *Buffer) InitFromRawBufferN(buff []byte, numBits int) error {
+	return f.InitFromRawBufferNWithBitOrder(buff, numBits, MSBFirst)
+}
+
+func (f *Buffer) InitFromRawBufferNWithBitOrder(buff []byte, numBits int, order BitOrder) error {
 	byteSize := getByteSize(numBits)
 	if byteSize > len(buff) {
 		return fmt.Errorf("not enough bits in init buffer")
 	}
 	f.bitSize = numBits
 	f.buffer = buff
+	f.order = order
+	f.offset = 0
+	f.view = false
 	return nil
 }
 
+func (f *Buffer) GetBitOrder() BitOrder {
+	return f.order
+}
+
 func (f *Buffer) UnsetAll() {
+	if f.view {
+		fillBits(f.buffer, f.order, f.offset, f.bitSize, false)
+		return
+	}
 	for i := range f.buffer {
 		f.buffer[i] = 0
 	}
 }
 
+// Slice returns a view of the bits [idx, idx+numBits) that shares the storage
+// of f, so writes through the view are seen by f and vice versa. Indexes of
+// the view are relative to idx. Growing the view (Write) detaches it from f,
+// and operations that compact or reallocate f (Read, GetRawBuffer after a
+// Read, Write) leave existing views pointing to stale storage.
+func (f *Buffer) Slice(reqidx int, numBits int) (view *Buffer, err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, numBits, -1); err != nil {
+		return nil, err
+	}
+	return &Buffer{
+		bitSize: numBits,
+		buffer:  f.buffer,
+		order:   f.order,
+		offset:  f.offset + idx,
+		view:    true,
+	}, nil
+}
+
+// IsView reports whether the buffer shares its storage with the buffer it was
+// sliced from.
+func (f *Buffer) IsView() bool {
+	return f.view
+}
+
 func (f *Buffer) SetBit(reqidx int, v bool) (err error) {
 	var idx int
 	if idx, err = f.parseParams(reqidx, 1, -1); err != nil {
 		return err
 	}
-	bytePos := int(idx / 8)
-	bitPos := idx % 8
+	pos := f.offset + idx
+	bytePos := pos / 8
+	mask := f.bitMask(pos % 8)
 	if v {
-		f.buffer[bytePos] |= (0x80 >> bitPos)
+		f.buffer[bytePos] |= mask
 	} else {
-		f.buffer[bytePos] &= ^(0x80 >> bitPos)
+		f.buffer[bytePos] &= ^mask
 	}
 	return nil
 }
@@ -56,9 +143,8 @@ func (f *Buffer) GetBit(reqidx int) (re bool, err error) {
 	if idx, err = f.parseParams(reqidx, 1, -1); err != nil {
 		return false, err
 	}
-	bytePos := int(idx / 8)
-	bitPos := idx % 8
-	return (f.buffer[bytePos] & (0x80 >> bitPos)) != 0, nil
+	pos := f.offset + idx
+	return (f.buffer[pos/8] & f.bitMask(pos%8)) != 0, nil
 }
 
 func (f *Buffer) SetBitsFromUint64(reqidx int, v uint64, size int) (err error) {
@@ -66,11 +152,7 @@ func (f *Buffer) SetBitsFromUint64(reqidx int, v uint64, size int) (err error) {
 	if idx, err = f.parseParams(reqidx, size, 64); err != nil {
 		return err
 	}
-	bit := 0
-	for i := idx + size - 1; i >= idx; i-- {
-		f.SetBit(i, (v&(uint64(1)<<bit)) != 0)
-		bit++
-	}
+	writeBits(f.buffer, f.order, f.offset+idx, size, v)
 	return nil
 }
 
@@ -79,18 +161,7 @@ func (f *Buffer) GetBitsToUint64(reqidx int, size int) (res uint64, err error) {
 	if idx, err = f.parseParams(reqidx, size, 64); err != nil {
 		return 0, err
 	}
-	var v uint64 = 0
-	bit := 0
-	for i := idx + size - 1; i >= idx; i-- {
-		bitValue, _ := f.GetBit(i)
-		if bitValue {
-			v |= (uint64(1) << bit)
-		} else {
-			v &= ^(uint64(1) << bit)
-		}
-		bit++
-	}
-	return v, nil
+	return readBits(f.buffer, f.order, f.offset+idx, size), nil
 }
 
 func (f *Buffer) SetBitsFromInt64(idx int, v int64, size int) error {
@@ -102,19 +173,8 @@ func (f *Buffer) GetBitsToInt64(reqidx int, size int) (res int64, err error) {
 	if idx, err = f.parseParams(reqidx, size, 64); err != nil {
 		return 0, err
 	}
-	var v uint64 = 0
-	bit := 0
-	var bitValue bool
-	for i := idx + size - 1; i >= idx; i-- {
-		bitValue, _ = f.GetBit(i)
-		if bitValue {
-			v |= (uint64(1) << bit)
-		} else {
-			v &= ^(uint64(1) << bit)
-		}
-		bit++
-	}
-	if bitValue {
+	v := readBits(f.buffer, f.order, f.offset+idx, size)
+	if size > 0 && size < 64 && v&(uint64(1)<<(size-1)) != 0 {
 		v |= (0xffffffffffffffff << size)
 	}
 	return int64(v), nil
@@ -125,13 +185,7 @@ func (f *Buffer) SetBitsFromRawBuffer(reqidx int, b []byte, size int) (err error
 	if idx, err = f.parseParams(reqidx, size, len(b)*8); err != nil {
 		return err
 	}
-	end := idx + size
-	for i := idx; i < end; i++ {
-		dstIdx := (i - idx)
-		dstBytePos := dstIdx / 8
-		dstBitPos := dstIdx % 8
-		f.SetBit(i, ((b[dstBytePos]<<dstBitPos)&0x80) != 0)
-	}
+	copyBits(f.buffer, f.offset+idx, b, 0, size, f.order)
 	return nil
 }
 
@@ -140,38 +194,76 @@ func (f *Buffer) GetBitsToRawBuffer(reqidx int, size int) (res []byte, err error
 	if idx, err = f.parseParams(reqidx, size, -1); err != nil {
 		return nil, err
 	}
-	resBufSize := getByteSize(size)
-	resBuf := make([]byte, resBufSize)
-	end := idx + size
-	for i := idx; i < end; i++ {
-		dstIdx := (i - idx)
-		dstBytePos := dstIdx / 8
-		dstBitPos := dstIdx % 8
-		bitVal, _ := f.GetBit(i)
-		if bitVal {
-			resBuf[dstBytePos] |= 0x80 >> dstBitPos
-		}
-	}
+	resBuf := make([]byte, getByteSize(size))
+	copyBits(resBuf, 0, f.buffer, f.offset+idx, size, f.order)
 	return resBuf, nil
 }
 
 func (f *Buffer) Write(input []byte, numBits int) (err error) {
+	if numBits < 0 {
+		return fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
 	inputBufferSize := len(input)
 	inputBufferBitSize := inputBufferSize * 8
 	if inputBufferBitSize < numBits {
 		return fmt.Errorf("input buffer not enough size")
 	}
-	freeBits := len(f.buffer)*8 - f.bitSize
-	if freeBits < numBits {
-		extraBits := numBits - freeBits
-		extraBufSize := getByteSize(extraBits)
-		f.buffer = append(f.buffer, make([]byte, extraBufSize)...)
-	}
 	originalBitSize := f.bitSize
-	f.bitSize += numBits
+	f.grow(numBits)
 	return f.SetBitsFromRawBuffer(originalBitSize, input, numBits)
 }
 
+// InsertBits inserts the first numBits bits of input before the bit at idx,
+// moving the following bits towards the end. idx may be equal to the bit
+// size (or -1) to append. Inserting into a view detaches it.
+func (f *Buffer) InsertBits(reqidx int, input []byte, numBits int) (err error) {
+	if numBits < 0 {
+		return fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
+	if len(input)*8 < numBits {
+		return fmt.Errorf("input buffer not enough size")
+	}
+	var idx int
+	if idx, err = f.parseParams(reqidx, 0, -1); err != nil {
+		return err
+	}
+	if sameArray(input, f.buffer) {
+		input = append([]byte{}, input...)
+	}
+	tailBits := f.bitSize - idx
+	f.grow(numBits)
+	pos := f.offset + idx
+	moveBits(f.buffer, f.order, pos+numBits, pos, tailBits)
+	copyBits(f.buffer, pos, input, 0, numBits, f.order)
+	return nil
+}
+
+// Prepend inserts the first numBits bits of input at the start of the buffer.
+func (f *Buffer) Prepend(input []byte, numBits int) error {
+	return f.InsertBits(0, input, numBits)
+}
+
+// DeleteBits removes the bits [idx, idx+numBits), moving the following bits
+// towards the start. Deleting from a view detaches it.
+func (f *Buffer) DeleteBits(reqidx int, numBits int) (err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, numBits, -1); err != nil {
+		return err
+	}
+	if f.view && numBits > 0 {
+		f.detach()
+	}
+	pos := f.offset + idx
+	moveBits(f.buffer, f.order, pos, pos+numBits, f.bitSize-idx-numBits)
+	f.bitSize -= numBits
+	fillBits(f.buffer, f.order, f.offset+f.bitSize, numBits, false)
+	return nil
+}
+
+// Read extracts numBits bits from the head of the buffer. It only advances
+// an internal offset; the consumed storage is released once it exceeds the
+// remaining one, so reading a buffer in small pieces is amortized O(1) per
+// bit.
 func (f *Buffer) Read(numBits int) (out *Buffer, err error) {
 	if f.bitSize < numBits {
 		numBits = f.bitSize
@@ -181,23 +273,13 @@ func (f *Buffer) Read(numBits int) (out *Buffer, err error) {
 		return nil, err
 	}
 	out = &Buffer{}
-	out.InitFromRawBufferN(outRaw, numBits)
-
-	byteSizeRead := getByteSize(numBits)
-	newByteSize := f.GetByteSize() - byteSizeRead
-	padding := numBits % 8
-	offset := byteSizeRead
-	if padding != 0 {
-		newByteSize += 1
-		offset -= 1
-	}
-	newBuffer := make([]byte, newByteSize)
-	for newIdx := 0; newIdx < len(newBuffer); newIdx++ {
-		lastIdx := newIdx + offset
-		newBuffer[newIdx] |= f.buffer[lastIdx] << padding
-	}
-	f.buffer = newBuffer
+	out.InitFromRawBufferNWithBitOrder(outRaw, numBits, f.order)
+
+	f.offset += numBits
 	f.bitSize -= numBits
+	if !f.view && f.offset*2 >= len(f.buffer)*8 {
+		f.compact()
+	}
 	return
 }
 
@@ -210,7 +292,7 @@ func (f *Buffer) ReadEnd(numBits int) (out *Buffer, err error) {
 		return nil, err
 	}
 	out = &Buffer{}
-	out.InitFromRawBufferN(outRaw, numBits)
+	out.InitFromRawBufferNWithBitOrder(outRaw, numBits, f.order)
 	f.bitSize -= numBits
 	return
 }
@@ -225,22 +307,91 @@ func (f *Buffer) GetByteSize() int {
 
 func (f *Buffer) GetCopy() *Buffer {
 	frame := &Buffer{}
-	frame.Init(f.bitSize)
-	copy(frame.buffer, f.buffer)
+	frame.InitWithBitOrder(f.bitSize, f.order)
+	copyBits(frame.buffer, 0, f.buffer, f.offset, f.bitSize, f.order)
 	return frame
 }
 
 func (f *Buffer) GetRawCopy() []byte {
+	if f.offset != 0 || f.view {
+		return f.normalized()
+	}
 	bcopy := make([]byte, len(f.buffer))
 	copy(bcopy, f.buffer)
 	return bcopy
 }
 
+// GetRawBuffer returns the storage of the buffer. For views it is only shared
+// when the view starts at a byte boundary; otherwise a copy is returned.
 func (f *Buffer) GetRawBuffer() []byte {
+	if f.view {
+		if f.offset%8 == 0 {
+			start := f.offset / 8
+			end := start + getByteSize(f.bitSize)
+			// no spare capacity, appending must not overwrite the parent
+			return f.buffer[start:end:end]
+		}
+		return f.normalized()
+	}
+	f.compact()
 	return f.buffer
 }
 
+// compact drops the storage consumed by Read so the first bit is placed at
+// the start of the raw buffer again. It always allocates, as the storage may
+// be the slice passed to InitFromRawBuffer, which must not be modified.
+func (f *Buffer) compact() {
+	if f.offset == 0 {
+		return
+	}
+	f.buffer = f.normalized()
+	f.offset = 0
+}
+
+// normalized returns a new raw buffer holding the content of the backing
+// buffer from offset on, shifted to start at bit 0. For views only the bits
+// of the view are copied.
+func (f *Buffer) normalized() []byte {
+	numBits := len(f.buffer)*8 - f.offset
+	if f.view {
+		numBits = f.bitSize
+	}
+	out := make([]byte, getByteSize(numBits))
+	copyBits(out, 0, f.buffer, f.offset, numBits, f.order)
+	return out
+}
+
+// detach gives a view its own storage.
+func (f *Buffer) detach() {
+	f.buffer = f.normalized()
+	f.offset = 0
+	f.view = false
+}
+
+// sharesStorage reports whether f and o may use the same backing array.
+func (f *Buffer) sharesStorage(o *Buffer) bool {
+	return sameArray(f.buffer, o.buffer)
+}
+
+// grow appends numBits zeroed bits at the end of the buffer.
+func (f *Buffer) grow(numBits int) {
+	if f.view && numBits > 0 {
+		f.detach()
+	}
+	freeBits := len(f.buffer)*8 - f.offset - f.bitSize
+	if freeBits < numBits {
+		extraBits := numBits - freeBits
+		extraBufSize := getByteSize(extraBits)
+		f.buffer = append(f.buffer, make([]byte, extraBufSize)...)
+	}
+	fillBits(f.buffer, f.order, f.offset+f.bitSize, numBits, false)
+	f.bitSize += numBits
+}
+
 func (f *Buffer) parseParams(idx int, reqSize int, fromToSize int) (newIndex int, err error) {
+	if reqSize < 0 {
+		return 0, fmt.Errorf("invalid size (%d)", reqSize)
+	}
 	actualIdx := idx
 	if actualIdx < 0 {
 		actualIdx = f.bitSize + idx - reqSize + 1
@@ -257,6 +408,22 @@ func (f *Buffer) parseParams(idx int, reqSize int, fromToSize int) (newIndex int
 	return actualIdx, nil
 }
 
+// bitMask returns the mask of the bit at position bitPos (0..7) inside a byte.
+func (f *Buffer) bitMask(bitPos int) byte {
+	if f.order == LSBFirst {
+		return 0x01 << bitPos
+	}
+	return 0x80 >> bitPos
+}
+
+// sameArray reports whether a and b are slices of the same array.
+func sameArray(a []byte, b []byte) bool {
+	if cap(a) == 0 || cap(b) == 0 {
+		return false
+	}
+	return &a[:cap(a)][cap(a)-1] == &b[:cap(b)][cap(b)-1]
+}
+
 func getByteSize(numBits int) int {
 	numBytes := numBits / 8
 	if numBits%8 != 0 {
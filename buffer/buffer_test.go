@@ -108,6 +108,20 @@ func Test_GetUint8Bits_RightBoundsError(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func Test_NegativeSizes(t *testing.T) {
+	frame := &Buffer{}
+	frame.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
+	_, err := frame.GetBitsToRawBuffer(0, -1)
+	require.NotNil(t, err)
+	require.NotNil(t, frame.SetBitsFromRawBuffer(0, []byte{0xff, 0xff}, -9))
+	_, err = frame.GetBitsToUint64(0, -4)
+	require.NotNil(t, err)
+	require.NotNil(t, frame.SetBitsFromUint64(0, 1, -4))
+	require.NotNil(t, frame.Write([]byte{0xff}, -3))
+	require.Equal(t, 32, frame.GetBitSize())
+	require.Equal(t, []byte{0x12, 0x34, 0x56, 0x78}, frame.GetRawBuffer())
+}
+
 func Test_GetUint8BitsRight(t *testing.T) {
 	frame := &Buffer{}
 	frame.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
@@ -224,3 +238,318 @@ func Test_ReadEnd_8(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, []byte{0x34}, out.GetRawBuffer())
 }
+
+// Unaligned reads used to drop the bits that the next byte must fill in
+// after the shift, e.g. 0x1223 read back as 0x1020.
+func Test_Read_4_Remaining(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferN([]byte{0x11, 0x22, 0x33, 0x4f}, 28)
+	_, err := buf.Read(4)
+	require.Nil(t, err)
+	require.Equal(t, 24, buf.GetBitSize())
+	v, err := buf.GetBitsToUint64(0, 24)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x122334), v)
+}
+
+func Test_LSBFirst_SetGetBit(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitWithBitOrder(16, LSBFirst)
+	require.Equal(t, LSBFirst, buf.GetBitOrder())
+	err := buf.SetBit(0, true)
+	require.Nil(t, err)
+	err = buf.SetBit(9, true)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x01, 0x02}, buf.GetRawCopy())
+	v, err := buf.GetBit(-7)
+	require.Nil(t, err)
+	require.True(t, v)
+}
+
+func Test_LSBFirst_GetUintBits(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferWithBitOrder([]byte{0x34, 0x12}, LSBFirst)
+	v, err := buf.GetBitsToUint64(0, 16)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x1234), v)
+	v, err = buf.GetBitsToUint64(4, 8)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x23), v)
+}
+
+func Test_BitOrder_RoundTrip(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(64, order)
+		err := buf.SetBitsFromUint64(3, 0x1abc, 13)
+		require.Nil(t, err)
+		err = buf.SetBitsFromInt64(17, -5, 5)
+		require.Nil(t, err)
+		u, err := buf.GetBitsToUint64(3, 13)
+		require.Nil(t, err)
+		require.Equal(t, uint64(0x1abc), u, order.String())
+		i, err := buf.GetBitsToInt64(17, 5)
+		require.Nil(t, err)
+		require.Equal(t, int64(-5), i, order.String())
+
+		raw, err := buf.GetBitsToRawBuffer(3, 19)
+		require.Nil(t, err)
+		other := &Buffer{}
+		other.InitWithBitOrder(30, order)
+		err = other.SetBitsFromRawBuffer(7, raw, 19)
+		require.Nil(t, err)
+		u, err = other.GetBitsToUint64(7, 13)
+		require.Nil(t, err)
+		require.Equal(t, uint64(0x1abc), u, order.String())
+		i, err = other.GetBitsToInt64(21, 5)
+		require.Nil(t, err)
+		require.Equal(t, int64(-5), i, order.String())
+	}
+}
+
+func Test_LSBFirst_WriteRead(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitWithBitOrder(0, LSBFirst)
+	err := buf.Write([]byte{0x55, 0x44, 0x3f}, 20)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x55, 0x44, 0x0f}, buf.GetRawCopy())
+	err = buf.Write([]byte{0x0a}, 4)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x55, 0x44, 0xaf}, buf.GetRawCopy())
+
+	out, err := buf.Read(4)
+	require.Nil(t, err)
+	require.Equal(t, LSBFirst, out.GetBitOrder())
+	require.Equal(t, []byte{0x05}, out.GetRawBuffer())
+	v, err := buf.GetBitsToUint64(0, 20)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0xaf445), v)
+
+	out, err = buf.ReadEnd(8)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xaf}, out.GetRawBuffer())
+	require.Equal(t, 12, buf.GetBitSize())
+}
+
+func Test_Read_Fifo(t *testing.T) {
+	raw := make([]byte, 1000)
+	for i := range raw {
+		raw[i] = byte(i * 7)
+	}
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		src := &Buffer{}
+		src.InitFromRawBufferWithBitOrder(raw, order)
+		buf := src.GetCopy()
+		pos := 0
+		for step := 1; buf.GetBitSize() > 0; step = step%13 + 1 {
+			out, err := buf.Read(step)
+			require.Nil(t, err)
+			for i := 0; i < out.GetBitSize(); i++ {
+				exp, _ := src.GetBit(pos + i)
+				v, _ := out.GetBit(i)
+				require.Equal(t, exp, v)
+			}
+			pos += out.GetBitSize()
+			require.Equal(t, src.GetBitSize()-pos, buf.GetBitSize())
+			if buf.GetBitSize() > 0 {
+				exp, _ := src.GetBit(pos)
+				v, _ := buf.GetBit(0)
+				require.Equal(t, exp, v)
+			}
+			err = buf.Write([]byte{0xff}, 0)
+			require.Nil(t, err)
+		}
+		require.Equal(t, src.GetBitSize(), pos)
+	}
+}
+
+func Test_Read_RawBufferSemantics(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferN([]byte{0x11, 0x22, 0x33, 0x4f}, 28)
+	_, err := buf.Read(4)
+	require.Nil(t, err)
+	_, err = buf.Read(1)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x24, 0x46, 0x69, 0xe0}, buf.GetRawCopy())
+	require.Equal(t, []byte{0x24, 0x46, 0x69, 0xe0}, buf.GetRawBuffer())
+	err = buf.Write([]byte{0xa0}, 3)
+	require.Nil(t, err)
+	require.Equal(t, 26, buf.GetBitSize())
+	v, err := buf.GetBitsToUint64(-1, 3)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x5), v)
+	c := buf.GetCopy()
+	require.Equal(t, []byte{0x24, 0x46, 0x69, 0x40}, c.GetRawBuffer())
+}
+
+func Test_Read_KeepsInputSlice(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		raw := []byte{0x11, 0x22, 0x33, 0x44}
+		buf := &Buffer{}
+		buf.InitFromRawBufferWithBitOrder(raw, order)
+		_, err := buf.Read(20)
+		require.Nil(t, err)
+		require.Equal(t, []byte{0x11, 0x22, 0x33, 0x44}, raw)
+		require.Equal(t, 12, buf.GetBitSize())
+
+		buf.InitFromRawBufferWithBitOrder(raw, order)
+		_, err = buf.Read(3)
+		require.Nil(t, err)
+		buf.GetRawBuffer()
+		require.Equal(t, []byte{0x11, 0x22, 0x33, 0x44}, raw)
+	}
+}
+
+func Benchmark_Read_SmallPieces(b *testing.B) {
+	raw := make([]byte, 16*1024)
+	for i := 0; i < b.N; i++ {
+		buf := &Buffer{}
+		buf.InitFromRawBuffer(raw)
+		for buf.GetBitSize() > 0 {
+			buf.Read(13)
+		}
+	}
+}
+
+func Test_Slice_WriteThrough(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
+	view, err := buf.Slice(4, 16)
+	require.Nil(t, err)
+	require.True(t, view.IsView())
+	require.Equal(t, 16, view.GetBitSize())
+	v, err := view.GetBitsToUint64(0, 16)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x2345), v)
+	v, err = view.GetBitsToUint64(-1, 4)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x5), v)
+	_, err = view.GetBit(16)
+	require.NotNil(t, err)
+
+	require.Nil(t, view.SetBitsFromUint64(-1, 0xabc, 12))
+	require.Equal(t, []byte{0x12, 0xab, 0xc6, 0x78}, buf.GetRawBuffer())
+	require.Equal(t, []byte{0x2a, 0xbc}, view.GetRawCopy())
+
+	view.UnsetAll()
+	require.Equal(t, []byte{0x10, 0x00, 0x06, 0x78}, buf.GetRawBuffer())
+
+	sub, err := view.Slice(-1, 3)
+	require.Nil(t, err)
+	sub.Not()
+	require.Equal(t, []byte{0x10, 0x00, 0x76, 0x78}, buf.GetRawBuffer())
+}
+
+func Test_Slice_Aligned_RawBuffer(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
+	view, err := buf.Slice(8, 12)
+	require.Nil(t, err)
+	raw := view.GetRawBuffer()
+	require.Equal(t, []byte{0x34, 0x56}, raw)
+	raw[0] = 0xff
+	require.Equal(t, []byte{0x12, 0xff, 0x56, 0x78}, buf.GetRawBuffer())
+
+	view, err = buf.Slice(8, 8)
+	require.Nil(t, err)
+	raw = view.GetRawBuffer()
+	_ = append(raw, 0xaa)
+	require.Equal(t, []byte{0x12, 0xff, 0x56, 0x78}, buf.GetRawBuffer())
+
+	_, err = buf.Slice(0, -3)
+	require.NotNil(t, err)
+}
+
+func Test_Slice_ReadAndWrite(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
+	view, err := buf.Slice(4, 16)
+	require.Nil(t, err)
+	out, err := view.Read(4)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x20}, out.GetRawBuffer())
+	require.Equal(t, 12, view.GetBitSize())
+	require.Nil(t, view.SetBitsFromUint64(0, 0, 4))
+	require.Equal(t, []byte{0x12, 0x04, 0x56, 0x78}, buf.GetRawBuffer())
+
+	// growing the view detaches it instead of overwriting the parent
+	require.Nil(t, view.Write([]byte{0xff}, 8))
+	require.False(t, view.IsView())
+	require.Equal(t, []byte{0x04, 0x5f, 0xf0}, view.GetRawBuffer())
+	require.Equal(t, []byte{0x12, 0x04, 0x56, 0x78}, buf.GetRawBuffer())
+}
+
+func Test_Slice_LogicOverlap(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer(make([]byte, 24))
+	buf.SetBitsFromUint64(0, 0xffffffffffffffff, 64)
+	buf.SetBitsFromUint64(64, 0xffffffffffffffff, 64)
+	a, _ := buf.Slice(0, 128)
+	b, _ := buf.Slice(4, 128)
+	require.Nil(t, b.Xor(a))
+	c, err := buf.OnesCount(0, 192)
+	require.Nil(t, err)
+	require.Equal(t, 8, c)
+}
+
+func Test_InsertDeleteBits(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferN([]byte{0x12, 0x34, 0x50}, 20)
+	require.Nil(t, buf.InsertBits(4, []byte{0xab}, 8))
+	require.Equal(t, 28, buf.GetBitSize())
+	v, err := buf.GetBitsToUint64(0, 28)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x1ab2345), v)
+
+	require.Nil(t, buf.InsertBits(-1, []byte{0xc0}, 2))
+	require.Nil(t, buf.Prepend([]byte{0x80}, 1))
+	require.Equal(t, 31, buf.GetBitSize())
+	v, err = buf.GetBitsToUint64(0, 31)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x1ab2345<<2|0x3|1<<30), v)
+
+	require.Nil(t, buf.DeleteBits(0, 1))
+	require.Nil(t, buf.DeleteBits(-1, 2))
+	require.Nil(t, buf.DeleteBits(4, 8))
+	require.Equal(t, 20, buf.GetBitSize())
+	require.Equal(t, []byte{0x12, 0x34, 0x50}, buf.GetRawCopy()[:3])
+
+	require.NotNil(t, buf.DeleteBits(15, 6))
+	require.NotNil(t, buf.InsertBits(21, []byte{0}, 1))
+	require.NotNil(t, buf.InsertBits(0, []byte{0}, 9))
+	require.NotNil(t, buf.InsertBits(0, []byte{0}, -3))
+	require.NotNil(t, buf.Prepend([]byte{0}, -3))
+	require.NotNil(t, buf.DeleteBits(0, -3))
+	require.Equal(t, 20, buf.GetBitSize())
+	require.Equal(t, []byte{0x12, 0x34, 0x50}, buf.GetRawCopy()[:3])
+}
+
+func Test_InsertBits_Unaligned_LSBFirst(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitWithBitOrder(0, LSBFirst)
+	w := NewBitWriter(buf)
+	for i := 0; i < 10; i++ {
+		require.Nil(t, w.WriteUint(uint64(i), 7))
+	}
+	// insert two 3-bit groups, then remove them again
+	require.Nil(t, buf.InsertBits(21, []byte{0x05}, 3))
+	require.Nil(t, buf.InsertBits(49, []byte{0x02}, 3))
+	v, err := buf.GetBitsToUint64(21, 3)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x5), v)
+	require.Nil(t, buf.DeleteBits(49, 3))
+	require.Nil(t, buf.DeleteBits(21, 3))
+	r := NewBitReader(buf)
+	for i := 0; i < 10; i++ {
+		v, err := r.ReadUint(7)
+		require.Nil(t, err)
+		require.Equal(t, uint64(i), v)
+	}
+}
+
+func Test_InsertBits_SelfInput(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer(append(make([]byte, 0, 8), 0xf0, 0x0f))
+	require.Nil(t, buf.InsertBits(8, buf.GetRawBuffer(), 16))
+	require.Equal(t, []byte{0xf0, 0xf0, 0x0f, 0x0f}, buf.GetRawBuffer())
+}
@@ -0,0 +1,125 @@
+package buffer
+
+import "math/bits"
+
+// OnesCount returns the number of set bits in the range [idx, idx+size).
+func (f *Buffer) OnesCount(reqidx int, size int) (count int, err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, size, -1); err != nil {
+		return 0, err
+	}
+	pos := f.offset + idx
+	for size > 0 {
+		take := 64
+		if take > size {
+			take = size
+		}
+		count += bits.OnesCount64(readBits(f.buffer, f.order, pos, take))
+		pos += take
+		size -= take
+	}
+	return count, nil
+}
+
+// LeadingZeros returns the number of clear bits at the start (lowest
+// indexes) of the range [idx, idx+size).
+func (f *Buffer) LeadingZeros(reqidx int, size int) (int, error) {
+	idx, err := f.parseParams(reqidx, size, -1)
+	if err != nil {
+		return 0, err
+	}
+	if next := f.scanForward(idx, idx+size, true); next >= 0 {
+		return next - idx, nil
+	}
+	return size, nil
+}
+
+// TrailingZeros returns the number of clear bits at the end (highest
+// indexes) of the range [idx, idx+size).
+func (f *Buffer) TrailingZeros(reqidx int, size int) (int, error) {
+	idx, err := f.parseParams(reqidx, size, -1)
+	if err != nil {
+		return 0, err
+	}
+	if prev := f.scanBackward(idx, idx+size, true); prev >= 0 {
+		return idx + size - 1 - prev, nil
+	}
+	return size, nil
+}
+
+// NextSet returns the index of the first set bit at or after from, or -1 if
+// there is none. It allows iterating the set bits as:
+//
+//	for i := b.NextSet(0); i >= 0; i = b.NextSet(i + 1) {}
+func (f *Buffer) NextSet(from int) int {
+	if from < 0 {
+		from = 0
+	}
+	return f.scanForward(from, f.bitSize, true)
+}
+
+// NextClear returns the index of the first clear bit at or after from, or -1
+// if there is none.
+func (f *Buffer) NextClear(from int) int {
+	if from < 0 {
+		from = 0
+	}
+	return f.scanForward(from, f.bitSize, false)
+}
+
+// PrevSet returns the index of the last set bit at or before from, or -1 if
+// there is none.
+func (f *Buffer) PrevSet(from int) int {
+	if from >= f.bitSize {
+		from = f.bitSize - 1
+	}
+	return f.scanBackward(0, from+1, true)
+}
+
+// scanForward returns the lowest index in [start, end) whose bit equals v,
+// or -1.
+func (f *Buffer) scanForward(start int, end int, v bool) int {
+	for idx := start; idx < end; {
+		take := 64
+		if take > end-idx {
+			take = end - idx
+		}
+		word := f.readFirstAtLSB(idx, take, v)
+		if word != 0 {
+			return idx + bits.TrailingZeros64(word)
+		}
+		idx += take
+	}
+	return -1
+}
+
+// scanBackward returns the highest index in [start, end) whose bit equals v,
+// or -1.
+func (f *Buffer) scanBackward(start int, end int, v bool) int {
+	for end > start {
+		take := 64
+		if take > end-start {
+			take = end - start
+		}
+		end -= take
+		word := f.readFirstAtLSB(end, take, v)
+		if word != 0 {
+			return end + 63 - bits.LeadingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// readFirstAtLSB reads take bits at idx, leaving the bit at idx as the least
+// significant of the result. When v is false the bits are inverted so the
+// clear bits are reported as set ones.
+func (f *Buffer) readFirstAtLSB(idx int, take int, v bool) uint64 {
+	word := readBits(f.buffer, f.order, f.offset+idx, take)
+	if f.order == MSBFirst {
+		word = bits.Reverse64(word) >> (64 - take)
+	}
+	if !v {
+		word = ^word & (0xffffffffffffffff >> (64 - take))
+	}
+	return word
+}
@@ -0,0 +1,98 @@
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Count_Basic(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x00, 0x30, 0x01, 0x00})
+	c, err := buf.OnesCount(0, 32)
+	require.Nil(t, err)
+	require.Equal(t, 3, c)
+	lz, err := buf.LeadingZeros(0, 32)
+	require.Nil(t, err)
+	require.Equal(t, 10, lz)
+	tz, err := buf.TrailingZeros(0, 32)
+	require.Nil(t, err)
+	require.Equal(t, 8, tz)
+	tz, err = buf.TrailingZeros(-10, 4)
+	require.Nil(t, err)
+	require.Equal(t, 4, tz)
+
+	set := []int{}
+	for i := buf.NextSet(0); i >= 0; i = buf.NextSet(i + 1) {
+		set = append(set, i)
+	}
+	require.Equal(t, []int{10, 11, 23}, set)
+	require.Equal(t, 12, buf.NextClear(10))
+	require.Equal(t, 11, buf.PrevSet(22))
+	require.Equal(t, 23, buf.PrevSet(100))
+	require.Equal(t, -1, buf.PrevSet(9))
+	require.Equal(t, -1, buf.NextSet(24))
+
+	_, err = buf.OnesCount(30, 3)
+	require.NotNil(t, err)
+}
+
+func Test_Count_MatchPerBit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 1000; iter++ {
+		buf := randomBuffer(rnd, 40, BitOrder(rnd.Intn(2)))
+		// sparse bitmap
+		for i := 0; i < 320; i++ {
+			if rnd.Intn(8) != 0 {
+				buf.SetBit(i, iter%2 == 0)
+			}
+		}
+		size := rnd.Intn(321)
+		idx := rnd.Intn(320 - size + 1)
+
+		ones, lz, tz := 0, -1, -1
+		for i := 0; i < size; i++ {
+			if v, _ := buf.GetBit(idx + i); v {
+				ones++
+				if lz < 0 {
+					lz = i
+				}
+				tz = size - 1 - i
+			}
+		}
+		if lz < 0 {
+			lz, tz = size, size
+		}
+		c, err := buf.OnesCount(idx, size)
+		require.Nil(t, err)
+		require.Equal(t, ones, c)
+		v, err := buf.LeadingZeros(idx, size)
+		require.Nil(t, err)
+		require.Equal(t, lz, v)
+		v, err = buf.TrailingZeros(idx, size)
+		require.Nil(t, err)
+		require.Equal(t, tz, v)
+
+		from := rnd.Intn(320)
+		nextSet, nextClear, prevSet := -1, -1, -1
+		for i := from; i < 320; i++ {
+			b, _ := buf.GetBit(i)
+			if b && nextSet < 0 {
+				nextSet = i
+			}
+			if !b && nextClear < 0 {
+				nextClear = i
+			}
+		}
+		for i := from; i >= 0; i-- {
+			if b, _ := buf.GetBit(i); b {
+				prevSet = i
+				break
+			}
+		}
+		require.Equal(t, nextSet, buf.NextSet(from))
+		require.Equal(t, nextClear, buf.NextClear(from))
+		require.Equal(t, prevSet, buf.PrevSet(from))
+	}
+}
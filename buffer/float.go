@@ -0,0 +1,138 @@
+package buffer
+
+import "math"
+
+// Floating point accessors. Values are stored as their IEEE 754 bit pattern
+// using SetBitsFromUint64, so they follow the bit order of the buffer (big
+// endian for MSBFirst buffers). 16-bit formats take and return float64 values
+// so they are rounded only once, to nearest even.
+
+func (f *Buffer) SetFloat16(idx int, v float64) error {
+	return f.SetBitsFromUint64(idx, encodeFloat(v, 5, 10), 16)
+}
+
+func (f *Buffer) GetFloat16(idx int) (float64, error) {
+	b, err := f.GetBitsToUint64(idx, 16)
+	if err != nil {
+		return 0, err
+	}
+	return decodeFloat(b, 5, 10), nil
+}
+
+func (f *Buffer) SetBFloat16(idx int, v float64) error {
+	return f.SetBitsFromUint64(idx, encodeFloat(v, 8, 7), 16)
+}
+
+func (f *Buffer) GetBFloat16(idx int) (float64, error) {
+	b, err := f.GetBitsToUint64(idx, 16)
+	if err != nil {
+		return 0, err
+	}
+	return decodeFloat(b, 8, 7), nil
+}
+
+func (f *Buffer) SetFloat32(idx int, v float32) error {
+	return f.SetBitsFromUint64(idx, uint64(math.Float32bits(v)), 32)
+}
+
+func (f *Buffer) GetFloat32(idx int) (float32, error) {
+	b, err := f.GetBitsToUint64(idx, 32)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(uint32(b)), nil
+}
+
+func (f *Buffer) SetFloat64(idx int, v float64) error {
+	return f.SetBitsFromUint64(idx, math.Float64bits(v), 64)
+}
+
+func (f *Buffer) GetFloat64(idx int) (float64, error) {
+	b, err := f.GetBitsToUint64(idx, 64)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(b), nil
+}
+
+// encodeFloat returns the bit pattern of v in a binary floating point format
+// with expBits exponent bits and mantBits mantissa bits (both smaller than
+// the float64 ones), rounding to nearest even. NaN payloads are truncated and
+// kept quiet.
+func encodeFloat(v float64, expBits uint, mantBits uint) uint64 {
+	b := math.Float64bits(v)
+	exp := int(b>>52) & 0x7ff
+	mant := b & (1<<52 - 1)
+	bias := 1<<(expBits-1) - 1
+	maxExp := 1<<expBits - 1
+	sign := b >> 63 << (expBits + mantBits)
+	inf := sign | uint64(maxExp)<<mantBits
+	if exp == 0x7ff {
+		if mant != 0 {
+			return inf | 1<<(mantBits-1) | mant>>(52-mantBits)
+		}
+		return inf
+	}
+	if exp == 0 {
+		// zero or float64 subnormal, far below the smallest subnormal of the
+		// target format
+		return sign
+	}
+	e := exp - 1023 + bias
+	if e >= maxExp {
+		return inf
+	}
+	var shift uint
+	var out uint64
+	full := mant | 1<<52
+	if e > 0 {
+		shift = 52 - mantBits
+		full = mant
+		out = uint64(e) << mantBits
+	} else {
+		shift = uint(53 - int(mantBits) - e)
+		if shift > 54 {
+			return sign
+		}
+	}
+	rem := full & (1<<shift - 1)
+	half := uint64(1) << (shift - 1)
+	out |= full >> shift
+	if rem > half || (rem == half && out&1 != 0) {
+		// a carry out of the mantissa increments the exponent
+		out++
+	}
+	if out >= uint64(maxExp)<<mantBits {
+		return inf
+	}
+	return sign | out
+}
+
+// decodeFloat is the inverse of encodeFloat. The conversion is exact.
+func decodeFloat(b uint64, expBits uint, mantBits uint) float64 {
+	bias := 1<<(expBits-1) - 1
+	maxExp := 1<<expBits - 1
+	negative := (b>>(expBits+mantBits))&1 != 0
+	exp := int(b>>mantBits) & maxExp
+	mant := b & (1<<mantBits - 1)
+	var v float64
+	switch exp {
+	case maxExp:
+		if mant != 0 {
+			nan := uint64(0x7ff)<<52 | mant<<(52-mantBits)
+			if negative {
+				nan |= 1 << 63
+			}
+			return math.Float64frombits(nan)
+		}
+		v = math.Inf(1)
+	case 0:
+		v = math.Ldexp(float64(mant), 1-bias-int(mantBits))
+	default:
+		v = math.Ldexp(float64(mant|1<<mantBits), exp-bias-int(mantBits))
+	}
+	if negative {
+		v = -v
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package buffer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Float16_KnownValues(t *testing.T) {
+	cases := []struct {
+		v    float64
+		bits uint64
+	}{
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{65504, 0x7bff},
+		{65519, 0x7bff},
+		{65520, 0x7c00},
+		{0.1, 0x2e66},
+		{1.0 / 3, 0x3555},
+		{math.Ldexp(1, -14), 0x0400},
+		{math.Ldexp(1, -24), 0x0001},
+		{math.Ldexp(1, -25), 0x0000},
+		{math.Ldexp(3, -26), 0x0001},
+		{math.Ldexp(3, -25), 0x0002},
+		{math.Ldexp(1023, -24), 0x03ff},
+		{math.Ldexp(2047, -25), 0x0400},
+		{math.Inf(1), 0x7c00},
+		{math.Inf(-1), 0xfc00},
+		{math.Copysign(0, -1), 0x8000},
+		{1e-300, 0x0000},
+		{1e300, 0x7c00},
+	}
+	buf := &Buffer{}
+	buf.Init(21)
+	for _, c := range cases {
+		require.Nil(t, buf.SetFloat16(5, c.v))
+		b, err := buf.GetBitsToUint64(5, 16)
+		require.Nil(t, err)
+		require.Equal(t, c.bits, b, "%v", c.v)
+	}
+	require.Nil(t, buf.SetFloat16(5, math.NaN()))
+	v, err := buf.GetFloat16(5)
+	require.Nil(t, err)
+	require.True(t, math.IsNaN(v))
+}
+
+func Test_BFloat16_KnownValues(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitWithBitOrder(16, LSBFirst)
+	require.Nil(t, buf.SetBFloat16(0, 1))
+	require.Equal(t, []byte{0x80, 0x3f}, buf.GetRawBuffer())
+	require.Nil(t, buf.SetBFloat16(0, math.Pi))
+	v, err := buf.GetBFloat16(0)
+	require.Nil(t, err)
+	require.Equal(t, 3.140625, v)
+	require.Nil(t, buf.SetBFloat16(0, math.MaxFloat32))
+	v, err = buf.GetBFloat16(0)
+	require.Nil(t, err)
+	require.True(t, math.IsInf(v, 1))
+}
+
+func Test_Float16_AllPatternsRoundTrip(t *testing.T) {
+	for _, format := range [][2]uint{{5, 10}, {8, 7}} {
+		for b := uint64(0); b < 1<<16; b++ {
+			v := decodeFloat(b, format[0], format[1])
+			if math.IsNaN(v) {
+				require.True(t, math.IsNaN(decodeFloat(encodeFloat(v, format[0], format[1]), format[0], format[1])))
+				continue
+			}
+			require.Equal(t, b, encodeFloat(v, format[0], format[1]))
+		}
+	}
+}
+
+func Test_EncodeFloat_MatchesFloat32(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		// cover normal, subnormal and overflowing float32 values
+		v := math.Ldexp(rnd.Float64()+0.5, rnd.Intn(300)-170)
+		if rnd.Intn(2) == 0 {
+			v = -v
+		}
+		require.Equal(t, uint64(math.Float32bits(float32(v))), encodeFloat(v, 8, 23), "%v", v)
+		require.Equal(t, float64(float32(v)), decodeFloat(encodeFloat(v, 8, 23), 8, 23))
+	}
+}
+
+func Test_Float32_Float64_Unaligned(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(100, order)
+		require.Nil(t, buf.SetFloat32(3, 123.123))
+		require.Nil(t, buf.SetFloat64(35, -321.321))
+		v32, err := buf.GetFloat32(3)
+		require.Nil(t, err)
+		require.Equal(t, float32(123.123), v32)
+		v64, err := buf.GetFloat64(35)
+		require.Nil(t, err)
+		require.Equal(t, -321.321, v64)
+		_, err = buf.GetFloat64(37)
+		require.NotNil(t, err)
+	}
+}
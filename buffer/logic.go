@@ -0,0 +1,110 @@
+package buffer
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+func (f *Buffer) And(src *Buffer) error {
+	return f.applyWholeOp(src, opAnd)
+}
+
+func (f *Buffer) Or(src *Buffer) error {
+	return f.applyWholeOp(src, opOr)
+}
+
+func (f *Buffer) Xor(src *Buffer) error {
+	return f.applyWholeOp(src, opXor)
+}
+
+// AndNot clears the bits of f that are set in src.
+func (f *Buffer) AndNot(src *Buffer) error {
+	return f.applyWholeOp(src, opAndNot)
+}
+
+// Not inverts every bit of the buffer.
+func (f *Buffer) Not() {
+	f.NotRange(0, f.bitSize)
+}
+
+// AndRange computes dst[dstIdx:dstIdx+size] &= src[srcIdx:srcIdx+size].
+// Indexes follow the same rules as the rest of accessors.
+func (f *Buffer) AndRange(dstIdx int, src *Buffer, srcIdx int, size int) error {
+	return f.applyOp(dstIdx, src, srcIdx, size, opAnd)
+}
+
+func (f *Buffer) OrRange(dstIdx int, src *Buffer, srcIdx int, size int) error {
+	return f.applyOp(dstIdx, src, srcIdx, size, opOr)
+}
+
+func (f *Buffer) XorRange(dstIdx int, src *Buffer, srcIdx int, size int) error {
+	return f.applyOp(dstIdx, src, srcIdx, size, opXor)
+}
+
+func (f *Buffer) AndNotRange(dstIdx int, src *Buffer, srcIdx int, size int) error {
+	return f.applyOp(dstIdx, src, srcIdx, size, opAndNot)
+}
+
+func (f *Buffer) NotRange(reqidx int, size int) (err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, size, -1); err != nil {
+		return err
+	}
+	pos := f.offset + idx
+	for size > 0 {
+		take := 64
+		if take > size {
+			take = size
+		}
+		writeBits(f.buffer, f.order, pos, take, ^readBits(f.buffer, f.order, pos, take))
+		pos += take
+		size -= take
+	}
+	return nil
+}
+
+func opAnd(a, b uint64) uint64    { return a & b }
+func opOr(a, b uint64) uint64     { return a | b }
+func opXor(a, b uint64) uint64    { return a ^ b }
+func opAndNot(a, b uint64) uint64 { return a &^ b }
+
+func (f *Buffer) applyWholeOp(src *Buffer, op func(a, b uint64) uint64) error {
+	if f.bitSize != src.bitSize {
+		return fmt.Errorf("length mismatch (dst: %d bits  src: %d bits)", f.bitSize, src.bitSize)
+	}
+	return f.applyOp(0, src, 0, f.bitSize, op)
+}
+
+func (f *Buffer) applyOp(reqDstIdx int, src *Buffer, reqSrcIdx int, size int, op func(a, b uint64) uint64) (err error) {
+	var dstIdx, srcIdx int
+	if dstIdx, err = f.parseParams(reqDstIdx, size, -1); err != nil {
+		return fmt.Errorf("dst: %w", err)
+	}
+	if srcIdx, err = src.parseParams(reqSrcIdx, size, -1); err != nil {
+		return fmt.Errorf("src: %w", err)
+	}
+	srcRaw := src.buffer
+	srcPos := src.offset + srcIdx
+	if src.sharesStorage(f) {
+		// the source range may be modified while it is read
+		srcRaw, _ = src.GetBitsToRawBuffer(srcIdx, size)
+		srcPos = 0
+	}
+	dstPos := f.offset + dstIdx
+	for size > 0 {
+		take := 64
+		if take > size {
+			take = size
+		}
+		a := readBits(f.buffer, f.order, dstPos, take)
+		b := readBits(srcRaw, src.order, srcPos, take)
+		if src.order != f.order {
+			b = bits.Reverse64(b) >> (64 - take)
+		}
+		writeBits(f.buffer, f.order, dstPos, take, op(a, b))
+		dstPos += take
+		srcPos += take
+		size -= take
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Logic_WholeBuffer(t *testing.T) {
+	newBuf := func(raw ...byte) *Buffer {
+		b := &Buffer{}
+		b.InitFromRawBufferN(raw, 12)
+		return b
+	}
+	a := newBuf(0xf0, 0xf0)
+	require.Nil(t, a.And(newBuf(0x3c, 0x3c)))
+	require.Equal(t, []byte{0x30, 0x30}, a.GetRawBuffer())
+	require.Nil(t, a.Or(newBuf(0x01, 0x01)))
+	require.Equal(t, []byte{0x31, 0x30}, a.GetRawBuffer())
+	require.Nil(t, a.Xor(newBuf(0xff, 0xff)))
+	require.Equal(t, []byte{0xce, 0xc0}, a.GetRawBuffer())
+	require.Nil(t, a.AndNot(newBuf(0x0f, 0xff)))
+	require.Equal(t, []byte{0xc0, 0x00}, a.GetRawBuffer())
+	a.Not()
+	require.Equal(t, []byte{0x3f, 0xf0}, a.GetRawBuffer())
+
+	other := &Buffer{}
+	other.Init(13)
+	err := a.Xor(other)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "length mismatch")
+}
+
+func Test_Logic_Ranges(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ops := []struct {
+		apply func(dst *Buffer, dstIdx int, src *Buffer, srcIdx int, size int) error
+		ref   func(a, b bool) bool
+	}{
+		{(*Buffer).AndRange, func(a, b bool) bool { return a && b }},
+		{(*Buffer).OrRange, func(a, b bool) bool { return a || b }},
+		{(*Buffer).XorRange, func(a, b bool) bool { return a != b }},
+		{(*Buffer).AndNotRange, func(a, b bool) bool { return a && !b }},
+	}
+	for iter := 0; iter < 500; iter++ {
+		for _, op := range ops {
+			dst := randomBuffer(rnd, 20, BitOrder(rnd.Intn(2)))
+			src := randomBuffer(rnd, 20, BitOrder(rnd.Intn(2)))
+			size := rnd.Intn(150)
+			dstIdx := rnd.Intn(160 - size + 1)
+			srcIdx := rnd.Intn(160 - size + 1)
+			orig := dst.GetCopy()
+			require.Nil(t, op.apply(dst, dstIdx, src, srcIdx, size))
+			for i := 0; i < 160; i++ {
+				exp, _ := orig.GetBit(i)
+				if i >= dstIdx && i < dstIdx+size {
+					s, _ := src.GetBit(srcIdx + i - dstIdx)
+					exp = op.ref(exp, s)
+				}
+				v, _ := dst.GetBit(i)
+				require.Equal(t, exp, v)
+			}
+		}
+	}
+}
+
+func Test_Logic_RangeErrors(t *testing.T) {
+	dst := &Buffer{}
+	dst.Init(16)
+	src := &Buffer{}
+	src.Init(8)
+	err := dst.XorRange(0, src, 0, 9)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "src")
+	err = dst.XorRange(10, src, 0, 8)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "dst")
+	require.NotNil(t, dst.NotRange(9, 8))
+}
+
+func Test_Logic_SameBufferOverlap(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0xaa, 0x00, 0x00})
+	require.Nil(t, buf.OrRange(4, buf, 0, 16))
+	require.Equal(t, []byte{0xaa, 0xa0, 0x00}, buf.GetRawBuffer())
+}
@@ -0,0 +1,99 @@
+package buffer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// binaryVersion is the first byte of the MarshalBinary encoding, which is
+// followed by a flags byte (bit 0 set for LSBFirst), the bit size as an
+// uvarint and the bytes holding the bits, with the padding bits cleared.
+const binaryVersion = 1
+
+const binaryFlagLSBFirst = 0x01
+
+// MarshalBinary implements encoding.BinaryMarshaler. It is also used by gob.
+// The Marshal methods have value receivers so Buffers stored by value in
+// structs are encoded too.
+func (f Buffer) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 2+binary.MaxVarintLen64+getByteSize(f.bitSize))
+	out[0] = binaryVersion
+	if f.order == LSBFirst {
+		out[1] |= binaryFlagLSBFirst
+	}
+	n := 2 + binary.PutUvarint(out[2:], uint64(f.bitSize))
+	out = out[:n+getByteSize(f.bitSize)]
+	copyBits(out[n:], 0, f.buffer, f.offset, f.bitSize, f.order)
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *Buffer) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("binary buffer too short (%d bytes)", len(data))
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("unsupported binary buffer version (%d)", data[0])
+	}
+	if data[1]&^binaryFlagLSBFirst != 0 {
+		return fmt.Errorf("invalid binary buffer flags (0x%02x)", data[1])
+	}
+	order := MSBFirst
+	if data[1]&binaryFlagLSBFirst != 0 {
+		order = LSBFirst
+	}
+	bitSize, n := binary.Uvarint(data[2:])
+	if n <= 0 || bitSize > uint64(len(data))*8 {
+		return fmt.Errorf("invalid binary buffer size")
+	}
+	raw := data[2+n:]
+	if uint64(len(raw)) != uint64(getByteSize(int(bitSize))) {
+		return fmt.Errorf("binary buffer length mismatch (expected: %d bytes  got: %d bytes)", getByteSize(int(bitSize)), len(raw))
+	}
+	f.InitWithBitOrder(int(bitSize), order)
+	copyBits(f.buffer, 0, raw, 0, f.bitSize, order)
+	return nil
+}
+
+type jsonBuffer struct {
+	BitOrder string `json:"bitOrder"`
+	Bits     string `json:"bits"`
+}
+
+// MarshalJSON implements json.Marshaler. Buffers are encoded as an object
+// holding the bit order and the text form of the bits:
+//
+//	{"bitOrder":"MSBFirst","bits":"0x2bd:10"}
+func (f Buffer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonBuffer{BitOrder: f.order.String(), Bits: f.formatHex(true, false)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides the MarshalJSON object,
+// it accepts a string with any of the forms of Parse, keeping the bit order
+// of f.
+func (f *Buffer) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return f.UnmarshalText([]byte(text))
+	}
+	var jb jsonBuffer
+	if err := json.Unmarshal(data, &jb); err != nil {
+		return err
+	}
+	var order BitOrder
+	switch jb.BitOrder {
+	case MSBFirst.String():
+		order = MSBFirst
+	case LSBFirst.String():
+		order = LSBFirst
+	default:
+		return fmt.Errorf("invalid bit order \"%s\"", jb.BitOrder)
+	}
+	b, err := ParseWithBitOrder(jb.Bits, order)
+	if err != nil {
+		return err
+	}
+	*f = *b
+	return nil
+}
@@ -0,0 +1,142 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MarshalBinary(t *testing.T) {
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferN([]byte{0xab, 0xcf}, 13))
+	data, err := buf.MarshalBinary()
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 0, 13, 0xab, 0xc8}, data)
+
+	out := &Buffer{}
+	require.Nil(t, out.UnmarshalBinary(data))
+	require.Equal(t, 13, out.GetBitSize())
+	require.Equal(t, MSBFirst, out.GetBitOrder())
+	require.Equal(t, []byte{0xab, 0xc8}, out.GetRawBuffer())
+
+	require.Nil(t, buf.InitFromRawBufferNWithBitOrder([]byte{0xab, 0xcf}, 13, LSBFirst))
+	data, err = buf.MarshalBinary()
+	require.Nil(t, err)
+	require.Equal(t, []byte{1, 1, 13, 0xab, 0x0f}, data)
+	require.Nil(t, out.UnmarshalBinary(data))
+	require.Equal(t, LSBFirst, out.GetBitOrder())
+	require.Equal(t, []byte{0xab, 0x0f}, out.GetRawBuffer())
+}
+
+func Test_MarshalBinary_Views(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x12, 0x34, 0x56, 0x78})
+	view, err := buf.Slice(4, 20)
+	require.Nil(t, err)
+	data, err := view.MarshalBinary()
+	require.Nil(t, err)
+	out := &Buffer{}
+	require.Nil(t, out.UnmarshalBinary(data))
+	require.Equal(t, "0x23456", out.String())
+	require.False(t, out.IsView())
+
+	// a big size needs a multi-byte uvarint
+	big := &Buffer{}
+	big.Init(1000)
+	require.Nil(t, big.SetBit(999, true))
+	data, err = big.MarshalBinary()
+	require.Nil(t, err)
+	require.Equal(t, 2+2+125, len(data))
+	require.Nil(t, out.UnmarshalBinary(data))
+	require.Equal(t, 1000, out.GetBitSize())
+	v, _ := out.GetBit(999)
+	require.True(t, v)
+}
+
+func Test_UnmarshalBinary_Errors(t *testing.T) {
+	out := &Buffer{}
+	for _, data := range [][]byte{
+		nil,
+		{1},
+		{2, 0, 8, 0xff},
+		{1, 2, 8, 0xff},
+		{1, 0, 16, 0xff},
+		{1, 0, 8, 0xff, 0xff},
+		{1, 0, 0x80},
+	} {
+		require.NotNil(t, out.UnmarshalBinary(data), "%x", data)
+	}
+}
+
+func Test_Gob(t *testing.T) {
+	type record struct {
+		Name string
+		Bits *Buffer
+	}
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferNWithBitOrder([]byte{0x5a, 0x03}, 11, LSBFirst))
+	var stream bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&stream).Encode(record{Name: "a", Bits: buf}))
+	var out record
+	require.Nil(t, gob.NewDecoder(&stream).Decode(&out))
+	require.Equal(t, 11, out.Bits.GetBitSize())
+	require.Equal(t, LSBFirst, out.Bits.GetBitOrder())
+	require.Equal(t, buf.GetRawCopy(), out.Bits.GetRawCopy())
+}
+
+func Test_JSON(t *testing.T) {
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferNWithBitOrder([]byte{0xaf, 0x02}, 10, LSBFirst))
+	data, err := json.Marshal(map[string]*Buffer{"bits": buf})
+	require.Nil(t, err)
+	require.Equal(t, `{"bits":{"bitOrder":"LSBFirst","bits":"0x3d5:10"}}`, string(data))
+
+	var out map[string]*Buffer
+	require.Nil(t, json.Unmarshal(data, &out))
+	require.Equal(t, 10, out["bits"].GetBitSize())
+	require.Equal(t, LSBFirst, out["bits"].GetBitOrder())
+	require.Equal(t, buf.GetRawCopy(), out["bits"].GetRawCopy())
+
+	// hand written strings are accepted too
+	require.Nil(t, json.Unmarshal([]byte(`{"bits":"0b101"}`), &out))
+	require.Equal(t, "101", fmt.Sprintf("%b", out["bits"]))
+
+	require.NotNil(t, json.Unmarshal([]byte(`{"bits":{"bitOrder":"Middle","bits":"0x1"}}`), &out))
+	require.NotNil(t, json.Unmarshal([]byte(`{"bits":{"bitOrder":"MSBFirst","bits":"0xq"}}`), &out))
+	require.NotNil(t, json.Unmarshal([]byte(`{"bits":12}`), &out))
+}
+
+func Test_Marshal_ByValue(t *testing.T) {
+	type record struct {
+		Name string
+		Bits Buffer
+	}
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferNWithBitOrder([]byte{0xaf, 0x02}, 10, LSBFirst))
+	in := record{Name: "a", Bits: *buf}
+
+	data, err := json.Marshal(in)
+	require.Nil(t, err)
+	require.Equal(t, `{"Name":"a","Bits":{"bitOrder":"LSBFirst","bits":"0x3d5:10"}}`, string(data))
+	var out record
+	require.Nil(t, json.Unmarshal(data, &out))
+	require.Equal(t, 10, out.Bits.GetBitSize())
+	require.Equal(t, LSBFirst, out.Bits.GetBitOrder())
+	require.Equal(t, buf.GetRawCopy(), out.Bits.GetRawCopy())
+
+	var stream bytes.Buffer
+	require.Nil(t, gob.NewEncoder(&stream).Encode(in))
+	out = record{}
+	require.Nil(t, gob.NewDecoder(&stream).Decode(&out))
+	require.Equal(t, 10, out.Bits.GetBitSize())
+	require.Equal(t, LSBFirst, out.Bits.GetBitOrder())
+	require.Equal(t, buf.GetRawCopy(), out.Bits.GetRawCopy())
+
+	text, err := in.Bits.MarshalText()
+	require.Nil(t, err)
+	require.Equal(t, "0x3d5:10", string(text))
+}
@@ -0,0 +1,120 @@
+package buffer
+
+import (
+	"fmt"
+	"math"
+)
+
+// OverflowMode selects what happens when a value doesn't fit in the number
+// of bits it is stored in.
+type OverflowMode int
+
+const (
+	// OverflowTruncate keeps the low bits of the value. This is what
+	// SetBitsFromUint64 and SetBitsFromInt64 do.
+	OverflowTruncate OverflowMode = iota
+	// OverflowCheck rejects the value with an *OverflowError.
+	OverflowCheck
+	// OverflowSaturate stores the closest representable value.
+	OverflowSaturate
+)
+
+func (m OverflowMode) String() string {
+	switch m {
+	case OverflowTruncate:
+		return "truncate"
+	case OverflowCheck:
+		return "check"
+	case OverflowSaturate:
+		return "saturate"
+	default:
+		return fmt.Sprintf("OverflowMode(%d)", int(m))
+	}
+}
+
+// OverflowError reports a value out of the range of a Size bits integer.
+type OverflowError struct {
+	Value  interface{}
+	Size   int
+	Signed bool
+	// Encoding of signed integers, which changes their minimum.
+	Encoding SignedEncoding
+}
+
+func (e *OverflowError) Error() string {
+	if e.Size > 64 {
+		min, max := bigRange(e.Size, e.Signed)
+		return fmt.Sprintf("value %v doesn't fit in %d bits (min: %v  max: %v)", e.Value, e.Size, min, max)
+	}
+	return fmt.Sprintf("value %v doesn't fit in %d bits (min: %d  max: %d)", e.Value, e.Size, e.Min(), e.Max())
+}
+
+// Min returns the smallest value that fits, for sizes up to 64 bits.
+func (e *OverflowError) Min() int64 {
+	if e.Signed {
+		min, _ := SignedRange(e.Size, e.Encoding)
+		return min
+	}
+	return 0
+}
+
+// Max returns the largest value that fits, for sizes up to 64 bits.
+func (e *OverflowError) Max() uint64 {
+	_, max := intRange(e.Size, e.Signed)
+	return max
+}
+
+// FitUint64 returns the value to store when v is written as a size bits
+// unsigned integer using mode.
+func FitUint64(v uint64, size int, mode OverflowMode) (uint64, error) {
+	_, max := intRange(size, false)
+	if v <= max || mode == OverflowTruncate {
+		return v, nil
+	}
+	if mode == OverflowSaturate {
+		return max, nil
+	}
+	return 0, &OverflowError{Value: v, Size: size}
+}
+
+// FitInt64 returns the value to store when v is written as a size bits two's
+// complement integer using mode.
+func FitInt64(v int64, size int, mode OverflowMode) (int64, error) {
+	return FitInt64WithEncoding(v, size, TwosComplement, mode)
+}
+
+// SetBitsFromUint64WithOverflow works as SetBitsFromUint64, handling values
+// wider than size bits as mode says.
+func (f *Buffer) SetBitsFromUint64WithOverflow(idx int, v uint64, size int, mode OverflowMode) (err error) {
+	if v, err = FitUint64(v, size, mode); err != nil {
+		return err
+	}
+	return f.SetBitsFromUint64(idx, v, size)
+}
+
+// SetBitsFromInt64WithOverflow works as SetBitsFromInt64, handling values
+// out of the range of a size bits signed integer as mode says.
+func (f *Buffer) SetBitsFromInt64WithOverflow(idx int, v int64, size int, mode OverflowMode) (err error) {
+	if v, err = FitInt64(v, size, mode); err != nil {
+		return err
+	}
+	return f.SetBitsFromInt64(idx, v, size)
+}
+
+// intRange returns the range of a size bits integer. The maximum of signed
+// integers is always below math.MaxInt64+1.
+func intRange(size int, signed bool) (min int64, max uint64) {
+	switch {
+	case size <= 0:
+		return 0, 0
+	case size >= 64:
+		if signed {
+			return math.MinInt64, math.MaxInt64
+		}
+		return 0, math.MaxUint64
+	case signed:
+		return -1 << (size - 1), 1<<(size-1) - 1
+	default:
+		return 0, 1<<size - 1
+	}
+}
@@ -0,0 +1,72 @@
+package buffer
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetBitsFromUint64WithOverflow(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(12)
+	require.Nil(t, buf.SetBitsFromUint64WithOverflow(0, 300, 4, OverflowTruncate))
+	v, _ := buf.GetBitsToUint64(0, 4)
+	require.Equal(t, uint64(0xc), v)
+
+	err := buf.SetBitsFromUint64WithOverflow(4, 300, 4, OverflowCheck)
+	require.EqualError(t, err, "value 300 doesn't fit in 4 bits (min: 0  max: 15)")
+	var oe *OverflowError
+	require.True(t, errors.As(err, &oe))
+	require.Equal(t, uint64(300), oe.Value)
+	require.Equal(t, uint64(15), oe.Max())
+	v, _ = buf.GetBitsToUint64(4, 4)
+	require.Equal(t, uint64(0), v, "failed writes don't modify the buffer")
+
+	require.Nil(t, buf.SetBitsFromUint64WithOverflow(4, 300, 4, OverflowSaturate))
+	require.Nil(t, buf.SetBitsFromUint64WithOverflow(8, 15, 4, OverflowCheck))
+	require.Equal(t, []byte{0xcf, 0xf0}, buf.GetRawBuffer())
+
+	_, err = FitUint64(math.MaxUint64, 64, OverflowCheck)
+	require.Nil(t, err)
+	_, err = FitUint64(1, 0, OverflowCheck)
+	require.NotNil(t, err)
+}
+
+func Test_SetBitsFromInt64WithOverflow(t *testing.T) {
+	cases := []struct {
+		v    int64
+		size int
+		sat  int64
+		ok   bool
+	}{
+		{7, 4, 7, true},
+		{-8, 4, -8, true},
+		{8, 4, 7, false},
+		{-9, 4, -8, false},
+		{300, 4, 7, false},
+		{-300, 9, -256, false},
+		{255, 9, 255, true},
+		{math.MinInt64, 64, math.MinInt64, true},
+		{math.MinInt64, 63, -1 << 62, false},
+		{math.MaxInt64, 63, 1<<62 - 1, false},
+	}
+	buf := &Buffer{}
+	buf.Init(70)
+	for _, c := range cases {
+		err := buf.SetBitsFromInt64WithOverflow(3, c.v, c.size, OverflowCheck)
+		if c.ok {
+			require.Nil(t, err, "%d in %d bits", c.v, c.size)
+		} else {
+			var oe *OverflowError
+			require.True(t, errors.As(err, &oe), "%d in %d bits", c.v, c.size)
+			require.True(t, oe.Signed)
+		}
+		require.Nil(t, buf.SetBitsFromInt64WithOverflow(3, c.v, c.size, OverflowSaturate))
+		v, _ := buf.GetBitsToInt64(3, c.size)
+		require.Equal(t, c.sat, v, "%d in %d bits", c.v, c.size)
+	}
+	_, err := FitInt64(-9, 4, OverflowCheck)
+	require.EqualError(t, err, "value -9 doesn't fit in 4 bits (min: -8  max: 7)")
+}
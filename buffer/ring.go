@@ -0,0 +1,173 @@
+package buffer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RingOverflowPolicy sets what RingBuffer.Write does when there is not
+// enough free space for the input.
+type RingOverflowPolicy int
+
+const (
+	// RingOverflowError makes Write fail without writing anything.
+	RingOverflowError RingOverflowPolicy = iota
+	// RingOverwrite discards the oldest bits to make room for the new ones.
+	RingOverwrite
+	// RingBlock makes Write wait until readers free enough space.
+	RingBlock
+)
+
+// RingBuffer is a fixed capacity FIFO of bits. Writes wrap around the end of
+// the storage, so no memory is allocated after creation apart from the
+// buffers returned by Read and Peek. It is safe for concurrent use.
+type RingBuffer struct {
+	storage Buffer
+	policy  RingOverflowPolicy
+	head    int
+	length  int
+	closed  bool
+	mutex   sync.Mutex
+	cond    *sync.Cond
+}
+
+func NewRingBuffer(capBits int, policy RingOverflowPolicy) *RingBuffer {
+	return NewRingBufferWithBitOrder(capBits, MSBFirst, policy)
+}
+
+func NewRingBufferWithBitOrder(capBits int, order BitOrder, policy RingOverflowPolicy) *RingBuffer {
+	r := &RingBuffer{policy: policy}
+	r.storage.InitWithBitOrder(capBits, order)
+	r.cond = sync.NewCond(&r.mutex)
+	return r
+}
+
+// Len returns the number of bits stored.
+func (r *RingBuffer) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.length
+}
+
+// Cap returns the capacity in bits.
+func (r *RingBuffer) Cap() int {
+	return r.storage.bitSize
+}
+
+// Free returns the number of bits that can be written without overflowing.
+func (r *RingBuffer) Free() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.Cap() - r.length
+}
+
+// Write appends the first numBits bits of input.
+func (r *RingBuffer) Write(input []byte, numBits int) error {
+	if numBits < 0 {
+		return fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
+	if len(input)*8 < numBits {
+		return fmt.Errorf("input buffer not enough size")
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	capBits := r.Cap()
+	srcPos := 0
+	if free := capBits - r.length; free < numBits {
+		switch r.policy {
+		case RingOverwrite:
+			if numBits > capBits {
+				srcPos = numBits - capBits
+				numBits = capBits
+			}
+			r.discard(numBits - (capBits - r.length))
+		case RingBlock:
+			if numBits > capBits {
+				return fmt.Errorf("write of %d bits exceeds ring capacity (%d)", numBits, capBits)
+			}
+			for !r.closed && capBits-r.length < numBits {
+				r.cond.Wait()
+			}
+			if r.closed {
+				return fmt.Errorf("ring buffer closed")
+			}
+		default:
+			return fmt.Errorf("ring buffer overflow (free: %d  requested: %d)", free, numBits)
+		}
+	}
+	if numBits == 0 {
+		return nil
+	}
+	tail := (r.head + r.length) % capBits
+	for done := 0; done < numBits; {
+		take := numBits - done
+		if tail+take > capBits {
+			take = capBits - tail
+		}
+		copyBits(r.storage.buffer, tail, input, srcPos+done, take, r.storage.order)
+		done += take
+		tail = (tail + take) % capBits
+	}
+	r.length += numBits
+	return nil
+}
+
+// Read extracts up to numBits of the oldest bits.
+func (r *RingBuffer) Read(numBits int) (*Buffer, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	out, err := r.peek(numBits)
+	if err != nil {
+		return nil, err
+	}
+	r.discard(out.bitSize)
+	r.cond.Broadcast()
+	return out, nil
+}
+
+// Peek works as Read but leaves the bits in the ring.
+func (r *RingBuffer) Peek(numBits int) (*Buffer, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.peek(numBits)
+}
+
+// Close wakes up writers blocked by RingBlock, making them fail.
+func (r *RingBuffer) Close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closed = true
+	r.cond.Broadcast()
+}
+
+func (r *RingBuffer) peek(numBits int) (*Buffer, error) {
+	if numBits < 0 {
+		return nil, fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
+	if numBits > r.length {
+		numBits = r.length
+	}
+	out := &Buffer{}
+	out.InitWithBitOrder(numBits, r.storage.order)
+	capBits := r.Cap()
+	pos := r.head
+	for done := 0; done < numBits; {
+		take := numBits - done
+		if pos+take > capBits {
+			take = capBits - pos
+		}
+		copyBits(out.buffer, done, r.storage.buffer, pos, take, r.storage.order)
+		done += take
+		pos = (pos + take) % capBits
+	}
+	return out, nil
+}
+
+func (r *RingBuffer) discard(numBits int) {
+	if r.length == numBits {
+		r.head = 0
+	} else {
+		r.head = (r.head + numBits) % r.Cap()
+	}
+	r.length -= numBits
+}
@@ -0,0 +1,104 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Ring_WrapAround(t *testing.T) {
+	r := NewRingBuffer(20, RingOverflowError)
+	require.Equal(t, 20, r.Cap())
+	require.Nil(t, r.Write([]byte{0x12, 0x34}, 12))
+	out, err := r.Read(8)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x12}, out.GetRawBuffer())
+	require.Nil(t, r.Write([]byte{0xab, 0xcd}, 16))
+	require.Equal(t, 20, r.Len())
+	require.Equal(t, 0, r.Free())
+
+	err = r.Write([]byte{0xff}, 1)
+	require.NotNil(t, err)
+
+	out, err = r.Peek(20)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x3a, 0xbc, 0xd0}, out.GetRawBuffer())
+	out, err = r.Read(30)
+	require.Nil(t, err)
+	require.Equal(t, 20, out.GetBitSize())
+	require.Equal(t, []byte{0x3a, 0xbc, 0xd0}, out.GetRawBuffer())
+	require.Equal(t, 0, r.Len())
+}
+
+func Test_Ring_Overwrite(t *testing.T) {
+	r := NewRingBuffer(16, RingOverwrite)
+	require.Nil(t, r.Write([]byte{0x12, 0x34}, 12))
+	require.Nil(t, r.Write([]byte{0x56}, 8))
+	require.Equal(t, 16, r.Len())
+	out, err := r.Peek(16)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x23, 0x56}, out.GetRawBuffer())
+
+	require.Nil(t, r.Write([]byte{0xab, 0xcd, 0xef}, 24))
+	out, err = r.Read(16)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xcd, 0xef}, out.GetRawBuffer())
+}
+
+func Test_Ring_InvalidNumBits(t *testing.T) {
+	for _, policy := range []RingOverflowPolicy{RingOverflowError, RingOverwrite, RingBlock} {
+		r := NewRingBuffer(16, policy)
+		require.Nil(t, r.Write([]byte{0x12}, 8))
+		require.NotNil(t, r.Write([]byte{0x34}, -4))
+		require.Equal(t, 8, r.Len())
+		_, err := r.Read(-1)
+		require.NotNil(t, err)
+		out, err := r.Read(8)
+		require.Nil(t, err)
+		require.Equal(t, []byte{0x12}, out.GetRawBuffer())
+	}
+}
+
+func Test_Ring_LSBFirst(t *testing.T) {
+	r := NewRingBufferWithBitOrder(12, LSBFirst, RingOverflowError)
+	require.Nil(t, r.Write([]byte{0x0d}, 4))
+	require.Nil(t, r.Write([]byte{0xab}, 8))
+	out, err := r.Read(4)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0x0d}, out.GetRawBuffer())
+	require.Nil(t, r.Write([]byte{0x07}, 4))
+	out, err = r.Read(12)
+	require.Nil(t, err)
+	v, err := out.GetBitsToUint64(0, 12)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x7ab), v)
+}
+
+func Test_Ring_Block(t *testing.T) {
+	r := NewRingBuffer(8, RingBlock)
+	require.Nil(t, r.Write([]byte{0xff}, 8))
+	done := make(chan error)
+	go func() {
+		done <- r.Write([]byte{0x00}, 4)
+	}()
+	select {
+	case <-done:
+		t.Fatal("write did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	_, err := r.Read(4)
+	require.Nil(t, err)
+	require.Nil(t, <-done)
+	out, err := r.Read(8)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xf0}, out.GetRawBuffer())
+
+	require.NotNil(t, r.Write([]byte{0, 0}, 9))
+	require.Nil(t, r.Write([]byte{0xff}, 8))
+	go func() {
+		done <- r.Write([]byte{0x00}, 1)
+	}()
+	r.Close()
+	require.NotNil(t, <-done)
+}
@@ -0,0 +1,107 @@
+package buffer
+
+import "math/bits"
+
+// Index returns the index of the first occurrence of pattern at or after
+// from, at any bit alignment, or -1 if there is none.
+func (f *Buffer) Index(pattern *Buffer, from int) int {
+	return f.IndexHamming(pattern, from, 0)
+}
+
+// LastIndex returns the index of the last occurrence of pattern starting at
+// or before from, or -1 if there is none.
+func (f *Buffer) LastIndex(pattern *Buffer, from int) int {
+	sp := f.shiftPattern(pattern)
+	if from > f.bitSize-sp.size {
+		from = f.bitSize - sp.size
+	}
+	for idx := from; idx >= 0; idx-- {
+		if f.matchAt(sp, idx, 0) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// IndexHamming works as Index but also accepts occurrences that differ from
+// pattern in up to maxDist bits.
+func (f *Buffer) IndexHamming(pattern *Buffer, from int, maxDist int) int {
+	idx, _ := f.IndexAnyHamming([]*Buffer{pattern}, from, maxDist)
+	return idx
+}
+
+// IndexAny returns the index of the first occurrence of any of patterns at or
+// after from, and the position of the matching pattern in the list. If
+// several patterns match at the same index the first one in the list is
+// reported. It returns -1, -1 if there is no occurrence.
+func (f *Buffer) IndexAny(patterns []*Buffer, from int) (idx int, pattern int) {
+	return f.IndexAnyHamming(patterns, from, 0)
+}
+
+// IndexAnyHamming works as IndexAny but also accepts occurrences that differ
+// from a pattern in up to maxDist bits.
+func (f *Buffer) IndexAnyHamming(patterns []*Buffer, from int, maxDist int) (idx int, pattern int) {
+	if from < 0 {
+		from = 0
+	}
+	sps := make([]*shiftedPattern, len(patterns))
+	for i, p := range patterns {
+		sps[i] = f.shiftPattern(p)
+	}
+	for idx := from; idx <= f.bitSize; idx++ {
+		for i, sp := range sps {
+			if idx+sp.size <= f.bitSize && f.matchAt(sp, idx, maxDist) {
+				return idx, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// shiftedPattern holds a copy of a pattern for each of the 8 possible bit
+// alignments inside a byte, laid out with the bit order of the buffer being
+// searched, so candidates are compared a whole byte at a time.
+type shiftedPattern struct {
+	size  int
+	bytes [8][]byte
+	masks [8][]byte
+}
+
+func (f *Buffer) shiftPattern(pattern *Buffer) *shiftedPattern {
+	sp := &shiftedPattern{size: pattern.GetBitSize()}
+	for align := 0; align < 8; align++ {
+		numBytes := getByteSize(align + sp.size)
+		b := make([]byte, numBytes)
+		m := make([]byte, numBytes)
+		for i := 0; i < sp.size; i++ {
+			if v, _ := pattern.GetBit(i); v {
+				writeBits(b, f.order, align+i, 1, 1)
+			}
+		}
+		fillBits(m, f.order, align, sp.size, true)
+		sp.bytes[align] = b
+		sp.masks[align] = m
+	}
+	return sp
+}
+
+// matchAt reports whether the pattern occurs at idx with at most maxDist
+// differing bits. idx+sp.size must not exceed the buffer size.
+func (f *Buffer) matchAt(sp *shiftedPattern, idx int, maxDist int) bool {
+	pos := f.offset + idx
+	align := pos & 7
+	hay := f.buffer[pos>>3:]
+	b := sp.bytes[align]
+	m := sp.masks[align]
+	dist := 0
+	for k := range b {
+		d := (hay[k] ^ b[k]) & m[k]
+		if d != 0 {
+			dist += bits.OnesCount8(d)
+			if dist > maxDist {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func naiveIndex(hay *Buffer, pattern *Buffer, from int, maxDist int) int {
+	for idx := from; idx+pattern.GetBitSize() <= hay.GetBitSize(); idx++ {
+		dist := 0
+		for i := 0; i < pattern.GetBitSize(); i++ {
+			a, _ := hay.GetBit(idx + i)
+			b, _ := pattern.GetBit(i)
+			if a != b {
+				dist++
+			}
+		}
+		if dist <= maxDist {
+			return idx
+		}
+	}
+	return -1
+}
+
+func Test_Index_SyncWord(t *testing.T) {
+	sync := &Buffer{}
+	sync.InitFromRawBuffer([]byte{0x47, 0x1d, 0xb3})
+	hay := &Buffer{}
+	hay.Init(200)
+	require.Nil(t, hay.SetBitsFromRawBuffer(77, sync.GetRawBuffer(), 24))
+	require.Nil(t, hay.SetBitsFromRawBuffer(150, sync.GetRawBuffer(), 24))
+
+	require.Equal(t, 77, hay.Index(sync, 0))
+	require.Equal(t, 77, hay.Index(sync, 77))
+	require.Equal(t, 150, hay.Index(sync, 78))
+	require.Equal(t, -1, hay.Index(sync, 151))
+	require.Equal(t, 150, hay.LastIndex(sync, 1000))
+	require.Equal(t, 77, hay.LastIndex(sync, 149))
+	require.Equal(t, -1, hay.LastIndex(sync, 76))
+
+	// corrupt two bits of the first occurrence
+	hay.SetBit(80, true)
+	hay.SetBit(90, false)
+	require.Equal(t, 150, hay.Index(sync, 0))
+	require.Equal(t, 150, hay.IndexHamming(sync, 0, 1))
+	require.Equal(t, 77, hay.IndexHamming(sync, 0, 2))
+}
+
+func Test_IndexAny(t *testing.T) {
+	a := &Buffer{}
+	a.InitFromRawBufferN([]byte{0xf8}, 5)
+	b := &Buffer{}
+	b.InitFromRawBufferN([]byte{0xa0}, 3)
+	hay := &Buffer{}
+	hay.InitFromRawBuffer([]byte{0x01, 0x40, 0x0f, 0x80})
+	idx, p := hay.IndexAny([]*Buffer{a, b}, 0)
+	require.Equal(t, 7, idx)
+	require.Equal(t, 1, p)
+	idx, p = hay.IndexAny([]*Buffer{a, b}, 8)
+	require.Equal(t, 20, idx)
+	require.Equal(t, 0, p)
+	idx, p = hay.IndexAny([]*Buffer{a, b}, 21)
+	require.Equal(t, -1, idx)
+	require.Equal(t, -1, p)
+}
+
+func Test_Index_MatchNaive(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 500; iter++ {
+		hay := randomBuffer(rnd, 16, BitOrder(rnd.Intn(2)))
+		hay.Read(rnd.Intn(8))
+		size := 1 + rnd.Intn(20)
+		start := rnd.Intn(hay.GetBitSize() - size)
+		raw, _ := hay.GetBitsToRawBuffer(start, size)
+		pattern := &Buffer{}
+		pattern.InitFromRawBufferNWithBitOrder(raw, size, BitOrder(rnd.Intn(2)))
+		if pattern.GetBitOrder() != hay.GetBitOrder() {
+			// keep the same bit sequence in the other order
+			pattern.Init(size)
+			for i := 0; i < size; i++ {
+				v, _ := hay.GetBit(start + i)
+				pattern.SetBit(i, v)
+			}
+		}
+		maxDist := rnd.Intn(3)
+		from := rnd.Intn(start + 1)
+		require.Equal(t, naiveIndex(hay, pattern, from, maxDist), hay.IndexHamming(pattern, from, maxDist))
+	}
+}
+
+func Benchmark_Index(b *testing.B) {
+	hay := randomBuffer(rand.New(rand.NewSource(1)), 1024, MSBFirst)
+	sync := &Buffer{}
+	sync.InitFromRawBuffer([]byte{0x47, 0x1d, 0xb3})
+	for i := 0; i < b.N; i++ {
+		hay.Index(sync, 0)
+	}
+}
+
+func Benchmark_Index_Naive(b *testing.B) {
+	hay := randomBuffer(rand.New(rand.NewSource(1)), 1024, MSBFirst)
+	sync := &Buffer{}
+	sync.InitFromRawBuffer([]byte{0x47, 0x1d, 0xb3})
+	for i := 0; i < b.N; i++ {
+		naiveIndex(hay, sync, 0, 0)
+	}
+}
@@ -0,0 +1,97 @@
+package buffer
+
+import "fmt"
+
+// Shifts and rotations move bits towards lower indexes (left) or towards
+// higher indexes (right), whatever the bit order of the buffer is.
+
+// ShiftLeft moves every bit n positions towards index 0. The vacated bits at
+// the end are set to fill.
+func (f *Buffer) ShiftLeft(n int, fill bool) error {
+	return f.ShiftLeftRange(0, f.bitSize, n, fill)
+}
+
+// ShiftRight moves every bit n positions towards the end. The vacated bits
+// at the start are set to fill.
+func (f *Buffer) ShiftRight(n int, fill bool) error {
+	return f.ShiftRightRange(0, f.bitSize, n, fill)
+}
+
+func (f *Buffer) RotateLeft(n int) error {
+	return f.RotateLeftRange(0, f.bitSize, n)
+}
+
+func (f *Buffer) RotateRight(n int) error {
+	return f.RotateRightRange(0, f.bitSize, n)
+}
+
+// ShiftLeftRange works as ShiftLeft over the range [idx, idx+size).
+func (f *Buffer) ShiftLeftRange(reqidx int, size int, n int, fill bool) (err error) {
+	var idx int
+	if idx, err = f.parseShiftParams(reqidx, size, n); err != nil {
+		return err
+	}
+	if n > size {
+		n = size
+	}
+	pos := f.offset + idx
+	moveBits(f.buffer, f.order, pos, pos+n, size-n)
+	fillBits(f.buffer, f.order, pos+size-n, n, fill)
+	return nil
+}
+
+// ShiftRightRange works as ShiftRight over the range [idx, idx+size).
+func (f *Buffer) ShiftRightRange(reqidx int, size int, n int, fill bool) (err error) {
+	var idx int
+	if idx, err = f.parseShiftParams(reqidx, size, n); err != nil {
+		return err
+	}
+	if n > size {
+		n = size
+	}
+	pos := f.offset + idx
+	moveBits(f.buffer, f.order, pos+n, pos, size-n)
+	fillBits(f.buffer, f.order, pos, n, fill)
+	return nil
+}
+
+// RotateLeftRange rotates the range [idx, idx+size) n positions towards
+// idx. A negative n rotates to the right.
+func (f *Buffer) RotateLeftRange(reqidx int, size int, n int) (err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, size, -1); err != nil {
+		return err
+	}
+	if size == 0 {
+		return nil
+	}
+	n %= size
+	if n < 0 {
+		n += size
+	}
+	if n == 0 {
+		return nil
+	}
+	pos := f.offset + idx
+	head := make([]byte, getByteSize(n))
+	copyBits(head, 0, f.buffer, pos, n, f.order)
+	moveBits(f.buffer, f.order, pos, pos+n, size-n)
+	copyBits(f.buffer, pos+size-n, head, 0, n, f.order)
+	return nil
+}
+
+// RotateRightRange rotates the range [idx, idx+size) n positions towards
+// the end. A negative n rotates to the left.
+func (f *Buffer) RotateRightRange(reqidx int, size int, n int) error {
+	if size > 0 {
+		n = size - n%size
+	}
+	return f.RotateLeftRange(reqidx, size, n)
+}
+
+func (f *Buffer) parseShiftParams(reqidx int, size int, n int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("invalid shift count (%d)", n)
+	}
+	return f.parseParams(reqidx, size, -1)
+}
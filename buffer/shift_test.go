@@ -0,0 +1,94 @@
+package buffer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Shift_WholeBuffer(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferN([]byte{0x12, 0x34, 0x50}, 20)
+	require.Nil(t, buf.ShiftLeft(4, false))
+	require.Equal(t, []byte{0x23, 0x45, 0x00}, buf.GetRawBuffer())
+	require.Nil(t, buf.ShiftRight(8, true))
+	require.Equal(t, []byte{0xff, 0x23, 0x40}, buf.GetRawBuffer())
+	require.Nil(t, buf.RotateLeft(12))
+	require.Equal(t, []byte{0x34, 0xff, 0x20}, buf.GetRawBuffer())
+	require.Nil(t, buf.RotateRight(12))
+	require.Equal(t, []byte{0xff, 0x23, 0x40}, buf.GetRawBuffer())
+	require.Nil(t, buf.ShiftLeft(30, false))
+	require.Equal(t, []byte{0x00, 0x00, 0x00}, buf.GetRawBuffer())
+	require.NotNil(t, buf.ShiftLeft(-1, false))
+}
+
+func Test_Shift_Ranges(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 2000; iter++ {
+		buf := randomBuffer(rnd, 24, BitOrder(rnd.Intn(2)))
+		if rnd.Intn(2) == 0 {
+			// exercise a non zero read offset
+			buf.Read(rnd.Intn(16))
+		}
+		bitSize := buf.GetBitSize()
+		size := rnd.Intn(bitSize + 1)
+		idx := rnd.Intn(bitSize - size + 1)
+		n := rnd.Intn(size + 8)
+		fill := rnd.Intn(2) == 0
+		orig := buf.GetCopy()
+
+		var ref func(i int) bool
+		switch iter % 4 {
+		case 0:
+			require.Nil(t, buf.ShiftLeftRange(idx, size, n, fill))
+			ref = func(i int) bool {
+				if i+n >= size {
+					return fill
+				}
+				v, _ := orig.GetBit(idx + i + n)
+				return v
+			}
+		case 1:
+			require.Nil(t, buf.ShiftRightRange(idx, size, n, fill))
+			ref = func(i int) bool {
+				if i < n {
+					return fill
+				}
+				v, _ := orig.GetBit(idx + i - n)
+				return v
+			}
+		case 2:
+			require.Nil(t, buf.RotateLeftRange(idx, size, n))
+			ref = func(i int) bool {
+				v, _ := orig.GetBit(idx + (i+n)%size)
+				return v
+			}
+		case 3:
+			require.Nil(t, buf.RotateRightRange(idx, size, n))
+			ref = func(i int) bool {
+				v, _ := orig.GetBit(idx + ((i-n)%size+size)%size)
+				return v
+			}
+		}
+		for i := 0; i < bitSize; i++ {
+			exp, _ := orig.GetBit(i)
+			if i >= idx && i < idx+size {
+				exp = ref(i - idx)
+			}
+			v, _ := buf.GetBit(i)
+			require.Equal(t, exp, v)
+		}
+	}
+}
+
+func Test_Shift_InvalidParams(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x12, 0x34})
+	require.NotNil(t, buf.RotateLeftRange(0, -3, 1))
+	require.NotNil(t, buf.RotateRightRange(0, -3, 1))
+	require.NotNil(t, buf.ShiftLeftRange(0, -3, 1, false))
+	require.NotNil(t, buf.ShiftRightRange(0, -3, 1, true))
+	require.NotNil(t, buf.ShiftLeft(-1, false))
+	require.Equal(t, []byte{0x12, 0x34}, buf.GetRawBuffer())
+}
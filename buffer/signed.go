@@ -0,0 +1,134 @@
+package buffer
+
+import "fmt"
+
+// SignedEncoding selects how signed integers are represented in bits.
+type SignedEncoding int
+
+const (
+	// TwosComplement is what SetBitsFromInt64 and GetBitsToInt64 use.
+	TwosComplement SignedEncoding = iota
+	// SignMagnitude stores the sign in the most significant bit and the
+	// absolute value in the rest.
+	SignMagnitude
+	// OnesComplement stores negative values inverting all the bits of the
+	// absolute value.
+	OnesComplement
+	// OffsetBinary (excess-K) stores v+2^(size-1) as an unsigned value.
+	OffsetBinary
+	// ZigZag stores ZigZagEncode(v), see transform.go.
+	ZigZag
+)
+
+func (e SignedEncoding) String() string {
+	switch e {
+	case TwosComplement:
+		return "two's complement"
+	case SignMagnitude:
+		return "sign-magnitude"
+	case OnesComplement:
+		return "one's complement"
+	case OffsetBinary:
+		return "offset binary"
+	case ZigZag:
+		return "zigzag"
+	default:
+		return fmt.Sprintf("SignedEncoding(%d)", int(e))
+	}
+}
+
+// SignedRange returns the range of a size bits signed integer using enc.
+// Sign-magnitude and one's complement have two zeros, so their range is
+// symmetric.
+func SignedRange(size int, enc SignedEncoding) (min int64, max int64) {
+	min, umax := intRange(size, true)
+	max = int64(umax)
+	if enc == SignMagnitude || enc == OnesComplement {
+		min = -max
+	}
+	return min, max
+}
+
+// FitInt64WithEncoding works as FitInt64 for the range of enc.
+func FitInt64WithEncoding(v int64, size int, enc SignedEncoding, mode OverflowMode) (int64, error) {
+	min, max := SignedRange(size, enc)
+	if (v >= min && v <= max) || mode == OverflowTruncate {
+		return v, nil
+	}
+	if mode == OverflowCheck {
+		return 0, &OverflowError{Value: v, Size: size, Signed: true, Encoding: enc}
+	}
+	if v < min {
+		return min, nil
+	}
+	return max, nil
+}
+
+// SetBitsFromInt64WithEncoding works as SetBitsFromInt64 using enc. Values
+// out of range are truncated to the size low bits of their representation.
+func (f *Buffer) SetBitsFromInt64WithEncoding(idx int, v int64, size int, enc SignedEncoding) error {
+	if size <= 0 || size > 64 {
+		return fmt.Errorf("invalid parameters")
+	}
+	mask := ^uint64(0) >> (64 - size)
+	sign := uint64(1) << (size - 1)
+	u := uint64(v)
+	switch enc {
+	case TwosComplement:
+	case SignMagnitude:
+		if v < 0 {
+			u = -u&(sign-1) | sign
+		} else {
+			u &= sign - 1
+		}
+	case OnesComplement:
+		if v < 0 {
+			u--
+		}
+	case OffsetBinary:
+		u ^= sign
+	case ZigZag:
+		u = ZigZagEncode(v)
+	default:
+		return fmt.Errorf("invalid signed encoding (%d)", int(enc))
+	}
+	return f.SetBitsFromUint64(idx, u&mask, size)
+}
+
+// GetBitsToInt64WithEncoding works as GetBitsToInt64 using enc. The negative
+// zeros of sign-magnitude and one's complement are returned as 0.
+func (f *Buffer) GetBitsToInt64WithEncoding(idx int, size int, enc SignedEncoding) (int64, error) {
+	if size <= 0 || size > 64 {
+		return 0, fmt.Errorf("invalid parameters")
+	}
+	u, err := f.GetBitsToUint64(idx, size)
+	if err != nil {
+		return 0, err
+	}
+	mask := ^uint64(0) >> (64 - size)
+	sign := uint64(1) << (size - 1)
+	switch enc {
+	case TwosComplement:
+	case SignMagnitude:
+		if u&sign != 0 {
+			return -int64(u &^ sign), nil
+		}
+		return int64(u), nil
+	case OnesComplement:
+		if u&sign != 0 {
+			return -int64(^u & mask), nil
+		}
+		return int64(u), nil
+	case OffsetBinary:
+		u ^= sign
+	case ZigZag:
+		return ZigZagDecode(u), nil
+	default:
+		return 0, fmt.Errorf("invalid signed encoding (%d)", int(enc))
+	}
+	// sign extension
+	if u&sign != 0 {
+		u |= ^mask
+	}
+	return int64(u), nil
+}
@@ -0,0 +1,123 @@
+package buffer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var signedEncodings = []SignedEncoding{TwosComplement, SignMagnitude, OnesComplement, OffsetBinary, ZigZag}
+
+func Test_SignedEncodings(t *testing.T) {
+	cases := []struct {
+		v    int64
+		enc  SignedEncoding
+		bits string
+	}{
+		{-3, TwosComplement, "11101"},
+		{-3, SignMagnitude, "10011"},
+		{-3, OnesComplement, "11100"},
+		{-3, OffsetBinary, "01101"},
+		{3, TwosComplement, "00011"},
+		{3, SignMagnitude, "00011"},
+		{3, OnesComplement, "00011"},
+		{3, OffsetBinary, "10011"},
+		{0, OffsetBinary, "10000"},
+		{-16, OffsetBinary, "00000"},
+		{15, OffsetBinary, "11111"},
+		{-15, SignMagnitude, "11111"},
+		{-15, OnesComplement, "10000"},
+		{-3, ZigZag, "00101"},
+		{3, ZigZag, "00110"},
+		{-16, ZigZag, "11111"},
+	}
+	for _, c := range cases {
+		buf := &Buffer{}
+		buf.Init(5)
+		require.Nil(t, buf.SetBitsFromInt64WithEncoding(0, c.v, 5, c.enc))
+		require.Equal(t, c.bits, fmt.Sprintf("%b", buf), "%d %v", c.v, c.enc)
+		v, err := buf.GetBitsToInt64WithEncoding(0, 5, c.enc)
+		require.Nil(t, err)
+		require.Equal(t, c.v, v, "%d %v", c.v, c.enc)
+	}
+
+	// negative zeros
+	for _, c := range []struct {
+		text string
+		enc  SignedEncoding
+	}{{"0b10000", SignMagnitude}, {"0b11111", OnesComplement}} {
+		buf, err := Parse(c.text)
+		require.Nil(t, err)
+		v, err := buf.GetBitsToInt64WithEncoding(0, 5, c.enc)
+		require.Nil(t, err)
+		require.Equal(t, int64(0), v)
+	}
+}
+
+func Test_SignedEncodings_RoundTrip(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for _, size := range []int{1, 5, 11, 13, 64} {
+			for _, enc := range signedEncodings {
+				min, max := SignedRange(size, enc)
+				values := []int64{min, max, 0}
+				if size < 16 {
+					values = values[:0]
+					for v := min; v <= max; v++ {
+						values = append(values, v)
+					}
+				}
+				buf := &Buffer{}
+				buf.InitWithBitOrder(size+3, order)
+				for _, v := range values {
+					require.Nil(t, buf.SetBitsFromInt64WithEncoding(3, v, size, enc))
+					out, err := buf.GetBitsToInt64WithEncoding(3, size, enc)
+					require.Nil(t, err)
+					require.Equal(t, v, out, "size %d %v", size, enc)
+				}
+			}
+		}
+	}
+}
+
+func Test_SignedRange(t *testing.T) {
+	min, max := SignedRange(11, TwosComplement)
+	require.Equal(t, []int64{-1024, 1023}, []int64{min, max})
+	min, max = SignedRange(11, OffsetBinary)
+	require.Equal(t, []int64{-1024, 1023}, []int64{min, max})
+	min, max = SignedRange(13, SignMagnitude)
+	require.Equal(t, []int64{-4095, 4095}, []int64{min, max})
+	min, max = SignedRange(64, OnesComplement)
+	require.Equal(t, []int64{-math.MaxInt64, math.MaxInt64}, []int64{min, max})
+}
+
+func Test_FitInt64WithEncoding(t *testing.T) {
+	v, err := FitInt64WithEncoding(-16, 5, SignMagnitude, OverflowSaturate)
+	require.Nil(t, err)
+	require.Equal(t, int64(-15), v)
+	v, err = FitInt64WithEncoding(-16, 5, OffsetBinary, OverflowCheck)
+	require.Nil(t, err)
+	require.Equal(t, int64(-16), v)
+
+	_, err = FitInt64WithEncoding(-16, 5, OnesComplement, OverflowCheck)
+	require.EqualError(t, err, "value -16 doesn't fit in 5 bits (min: -15  max: 15)")
+	var oe *OverflowError
+	require.True(t, errors.As(err, &oe))
+	require.Equal(t, OnesComplement, oe.Encoding)
+}
+
+func Test_SignedEncodings_Errors(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(8)
+	require.NotNil(t, buf.SetBitsFromInt64WithEncoding(0, 1, 0, SignMagnitude))
+	require.NotNil(t, buf.SetBitsFromInt64WithEncoding(0, 1, 65, SignMagnitude))
+	require.NotNil(t, buf.SetBitsFromInt64WithEncoding(0, 1, 9, SignMagnitude))
+	require.NotNil(t, buf.SetBitsFromInt64WithEncoding(0, 1, 8, SignedEncoding(7)))
+	_, err := buf.GetBitsToInt64WithEncoding(0, 8, SignedEncoding(7))
+	require.NotNil(t, err)
+	_, err = buf.GetBitsToInt64WithEncoding(4, 5, OffsetBinary)
+	require.NotNil(t, err)
+	require.Equal(t, "SignedEncoding(7)", SignedEncoding(7).String())
+}
@@ -0,0 +1,293 @@
+package buffer
+
+import (
+	"fmt"
+	"io"
+)
+
+// BitReader reads a Buffer sequentially, keeping a bit cursor. Reading does
+// not modify the underlying buffer.
+type BitReader struct {
+	buf *Buffer
+	pos int
+}
+
+func NewBitReader(buf *Buffer) *BitReader {
+	return &BitReader{buf: buf}
+}
+
+// Pos returns the cursor position in bits.
+func (r *BitReader) Pos() int {
+	return r.pos
+}
+
+// Remaining returns the number of bits left after the cursor.
+func (r *BitReader) Remaining() int {
+	rem := r.buf.GetBitSize() - r.pos
+	if rem < 0 {
+		return 0
+	}
+	return rem
+}
+
+func (r *BitReader) ReadBits(numBits int) (out *Buffer, err error) {
+	if err = r.check(numBits); err != nil {
+		return nil, err
+	}
+	outRaw, err := r.buf.GetBitsToRawBuffer(r.pos, numBits)
+	if err != nil {
+		return nil, err
+	}
+	out = &Buffer{}
+	out.InitFromRawBufferNWithBitOrder(outRaw, numBits, r.buf.GetBitOrder())
+	r.pos += numBits
+	return out, nil
+}
+
+func (r *BitReader) ReadUint(numBits int) (v uint64, err error) {
+	if err = r.check(numBits); err != nil {
+		return 0, err
+	}
+	if v, err = r.buf.GetBitsToUint64(r.pos, numBits); err != nil {
+		return 0, err
+	}
+	r.pos += numBits
+	return v, nil
+}
+
+func (r *BitReader) ReadInt(numBits int) (v int64, err error) {
+	if err = r.check(numBits); err != nil {
+		return 0, err
+	}
+	if v, err = r.buf.GetBitsToInt64(r.pos, numBits); err != nil {
+		return 0, err
+	}
+	r.pos += numBits
+	return v, nil
+}
+
+func (r *BitReader) ReadBool() (v bool, err error) {
+	if err = r.check(1); err != nil {
+		return false, err
+	}
+	if v, err = r.buf.GetBit(r.pos); err != nil {
+		return false, err
+	}
+	r.pos++
+	return v, nil
+}
+
+// Skip advances the cursor numBits bits.
+func (r *BitReader) Skip(numBits int) error {
+	if err := r.check(numBits); err != nil {
+		return err
+	}
+	r.pos += numBits
+	return nil
+}
+
+// Align advances the cursor to the next byte boundary.
+func (r *BitReader) Align() error {
+	return r.Skip(alignPadding(r.pos))
+}
+
+// Read implements io.Reader. Only whole bytes are read; a trailing group of
+// less than 8 bits is reported as io.ErrUnexpectedEOF.
+func (r *BitReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	rem := r.Remaining()
+	if rem == 0 {
+		return 0, io.EOF
+	}
+	n = rem / 8
+	if n == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if n > len(p) {
+		n = len(p)
+	}
+	data, err := r.buf.GetBitsToRawBuffer(r.pos, n*8)
+	if err != nil {
+		return 0, err
+	}
+	copy(p, data)
+	r.pos += n * 8
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader.
+func (r *BitReader) ReadByte() (byte, error) {
+	v, err := r.ReadUint(8)
+	return byte(v), err
+}
+
+// Seek implements io.Seeker using byte units.
+func (r *BitReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.SeekBit(offset*8, whence)
+	return pos / 8, err
+}
+
+// SeekBit works as Seek but using bit units.
+func (r *BitReader) SeekBit(offset int64, whence int) (int64, error) {
+	pos, err := seekPos(r.pos, r.buf.GetBitSize(), offset, whence)
+	if err != nil {
+		return int64(r.pos), err
+	}
+	r.pos = pos
+	return int64(pos), nil
+}
+
+func (r *BitReader) check(numBits int) error {
+	if numBits < 0 {
+		return fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
+	rem := r.Remaining()
+	if rem < numBits {
+		if rem == 0 && numBits > 0 {
+			return io.EOF
+		}
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// BitWriter writes a Buffer sequentially, keeping a bit cursor. Bits written
+// before the end of the buffer overwrite the existing ones; bits written past
+// the end grow the buffer.
+type BitWriter struct {
+	buf *Buffer
+	pos int
+}
+
+// NewBitWriter returns a writer with the cursor placed at the end of buf.
+func NewBitWriter(buf *Buffer) *BitWriter {
+	return &BitWriter{buf: buf, pos: buf.GetBitSize()}
+}
+
+// Pos returns the cursor position in bits.
+func (w *BitWriter) Pos() int {
+	return w.pos
+}
+
+func (w *BitWriter) WriteBits(input []byte, numBits int) error {
+	if len(input)*8 < numBits {
+		return fmt.Errorf("input buffer not enough size")
+	}
+	if err := w.reserve(numBits); err != nil {
+		return err
+	}
+	if err := w.buf.SetBitsFromRawBuffer(w.pos, input, numBits); err != nil {
+		return err
+	}
+	w.pos += numBits
+	return nil
+}
+
+func (w *BitWriter) WriteUint(v uint64, numBits int) error {
+	if numBits > 64 {
+		return fmt.Errorf("not enough bits (available: 64  requested: %d)", numBits)
+	}
+	if err := w.reserve(numBits); err != nil {
+		return err
+	}
+	if err := w.buf.SetBitsFromUint64(w.pos, v, numBits); err != nil {
+		return err
+	}
+	w.pos += numBits
+	return nil
+}
+
+func (w *BitWriter) WriteInt(v int64, numBits int) error {
+	return w.WriteUint(uint64(v), numBits)
+}
+
+func (w *BitWriter) WriteBool(v bool) error {
+	if err := w.reserve(1); err != nil {
+		return err
+	}
+	if err := w.buf.SetBit(w.pos, v); err != nil {
+		return err
+	}
+	w.pos++
+	return nil
+}
+
+// Skip advances the cursor numBits bits. Bits skipped past the end of the
+// buffer are zeroed.
+func (w *BitWriter) Skip(numBits int) error {
+	if err := w.reserve(numBits); err != nil {
+		return err
+	}
+	w.pos += numBits
+	return nil
+}
+
+// Align advances the cursor to the next byte boundary.
+func (w *BitWriter) Align() error {
+	return w.Skip(alignPadding(w.pos))
+}
+
+// Write implements io.Writer.
+func (w *BitWriter) Write(p []byte) (n int, err error) {
+	if err = w.WriteBits(p, len(p)*8); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteByte implements io.ByteWriter.
+func (w *BitWriter) WriteByte(c byte) error {
+	return w.WriteUint(uint64(c), 8)
+}
+
+// Seek implements io.Seeker using byte units. The cursor may be placed past
+// the end of the buffer; the gap is zero filled on the next write.
+func (w *BitWriter) Seek(offset int64, whence int) (int64, error) {
+	pos, err := w.SeekBit(offset*8, whence)
+	return pos / 8, err
+}
+
+// SeekBit works as Seek but using bit units.
+func (w *BitWriter) SeekBit(offset int64, whence int) (int64, error) {
+	pos, err := seekPos(w.pos, w.buf.GetBitSize(), offset, whence)
+	if err != nil {
+		return int64(w.pos), err
+	}
+	w.pos = pos
+	return int64(pos), nil
+}
+
+// reserve makes sure numBits bits are available from the cursor.
+func (w *BitWriter) reserve(numBits int) error {
+	if numBits < 0 {
+		return fmt.Errorf("invalid number of bits (%d)", numBits)
+	}
+	if end := w.pos + numBits; end > w.buf.GetBitSize() {
+		w.buf.grow(end - w.buf.GetBitSize())
+	}
+	return nil
+}
+
+func seekPos(current int, size int, offset int64, whence int) (int, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = int64(current) + offset
+	case io.SeekEnd:
+		pos = int64(size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence (%d)", whence)
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position (%d)", pos)
+	}
+	return int(pos), nil
+}
+
+func alignPadding(pos int) int {
+	return (8 - pos%8) % 8
+}
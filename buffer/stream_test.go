@@ -0,0 +1,115 @@
+package buffer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BitWriter_BitReader(t *testing.T) {
+	buf := &Buffer{}
+	w := NewBitWriter(buf)
+	require.Nil(t, w.WriteUint(0x5, 3))
+	require.Nil(t, w.WriteInt(-7, 5))
+	require.Nil(t, w.WriteBool(true))
+	require.Nil(t, w.Align())
+	require.Nil(t, w.WriteByte(0xa5))
+	n, err := w.Write([]byte{0x12, 0x34})
+	require.Nil(t, err)
+	require.Equal(t, 2, n)
+	require.Nil(t, w.WriteBits([]byte{0xf0}, 4))
+	require.Equal(t, 44, buf.GetBitSize())
+	require.Equal(t, []byte{0xb9, 0x80, 0xa5, 0x12, 0x34, 0xf0}, buf.GetRawCopy())
+
+	r := NewBitReader(buf)
+	u, err := r.ReadUint(3)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x5), u)
+	i, err := r.ReadInt(5)
+	require.Nil(t, err)
+	require.Equal(t, int64(-7), i)
+	b, err := r.ReadBool()
+	require.Nil(t, err)
+	require.True(t, b)
+	require.Nil(t, r.Align())
+	require.Equal(t, 16, r.Pos())
+	c, err := r.ReadByte()
+	require.Nil(t, err)
+	require.Equal(t, byte(0xa5), c)
+	p := make([]byte, 4)
+	n, err = r.Read(p)
+	require.Nil(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []byte{0x12, 0x34}, p[:n])
+	_, err = r.Read(p)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	out, err := r.ReadBits(4)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xf0}, out.GetRawBuffer())
+	_, err = r.ReadBool()
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_BitReader_Seek(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0x11, 0x22, 0x33, 0x44})
+	r := NewBitReader(buf)
+	pos, err := r.Seek(1, io.SeekStart)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), pos)
+	c, err := r.ReadByte()
+	require.Nil(t, err)
+	require.Equal(t, byte(0x22), c)
+
+	bitPos, err := r.SeekBit(-12, io.SeekEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(20), bitPos)
+	u, err := r.ReadUint(8)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x34), u)
+
+	_, err = r.SeekBit(-40, io.SeekCurrent)
+	require.NotNil(t, err)
+	require.Equal(t, 28, r.Pos())
+	require.Nil(t, r.Skip(4))
+	require.Equal(t, 0, r.Remaining())
+	require.Equal(t, io.EOF, r.Skip(1))
+}
+
+func Test_BitWriter_Overwrite(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBuffer([]byte{0xff, 0xff})
+	w := NewBitWriter(buf)
+	_, err := w.SeekBit(4, io.SeekStart)
+	require.Nil(t, err)
+	require.Nil(t, w.WriteUint(0, 4))
+	require.Equal(t, []byte{0xf0, 0xff}, buf.GetRawBuffer())
+
+	_, err = w.Seek(3, io.SeekStart)
+	require.Nil(t, err)
+	require.Nil(t, w.WriteBool(true))
+	require.Equal(t, 25, buf.GetBitSize())
+	require.Equal(t, []byte{0xf0, 0xff, 0x00, 0x80}, buf.GetRawBuffer())
+}
+
+func Test_BitReader_LSBFirst(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitWithBitOrder(0, LSBFirst)
+	w := NewBitWriter(buf)
+	require.Nil(t, w.WriteUint(0x3, 2))
+	require.Nil(t, w.WriteUint(0x1234, 14))
+	require.Nil(t, w.WriteByte(0x5a))
+
+	r := NewBitReader(buf)
+	u, err := r.ReadUint(2)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x3), u)
+	u, err = r.ReadUint(14)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0x1234), u)
+	c, err := r.ReadByte()
+	require.Nil(t, err)
+	require.Equal(t, byte(0x5a), c)
+	require.Equal(t, byte(0x5a), buf.GetRawBuffer()[2])
+}
@@ -0,0 +1,182 @@
+package buffer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Text forms describe the bit sequence of a buffer in index order, whatever
+// its bit order is, and always hold exactly GetBitSize bits:
+//
+//	%b   bits, e.g. 1010111101 (0b1010111101 with %#b)
+//	%x   the bits as a number whose most significant bit is the bit 0, in
+//	     hex. When the size isn't a multiple of 4 the number of bits follows
+//	     a colon, e.g. 2bd:10 (0x2bd:10 with %#x). %X uses upper case digits
+//	%v   same as %#x
+//
+// For byte aligned MSBFirst buffers %x is the usual hex dump of the bytes.
+
+// Format implements fmt.Formatter.
+func (f *Buffer) Format(s fmt.State, verb rune) {
+	var text string
+	switch verb {
+	case 'b':
+		text = f.formatBin(s.Flag('#'))
+	case 'x', 'X':
+		text = f.formatHex(s.Flag('#'), verb == 'X')
+	case 'v', 's':
+		text = f.formatHex(true, false)
+	default:
+		fmt.Fprintf(s, "%%!%c(*buffer.Buffer=%s)", verb, f.formatHex(true, false))
+		return
+	}
+	if width, ok := s.Width(); ok && width > len(text) {
+		pad := strings.Repeat(" ", width-len(text))
+		if s.Flag('-') {
+			text += pad
+		} else {
+			text = pad + text
+		}
+	}
+	fmt.Fprint(s, text)
+}
+
+func (f *Buffer) String() string {
+	return f.formatHex(true, false)
+}
+
+// MarshalText implements encoding.TextMarshaler using the %v form.
+func (f Buffer) MarshalText() ([]byte, error) {
+	return []byte(f.formatHex(true, false)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the forms of
+// Parse and keeps the bit order of f.
+func (f *Buffer) UnmarshalText(text []byte) error {
+	b, err := ParseWithBitOrder(string(text), f.order)
+	if err != nil {
+		return err
+	}
+	*f = *b
+	return nil
+}
+
+// Parse returns a MSBFirst buffer holding the bits of s, which may be
+//   - binary digits, optionally prefixed by 0b: "0b1010_1111_01", "1010 0110"
+//   - hex digits prefixed by 0x, each one standing for 4 bits: "0x1F"
+//
+// and may end with a colon and a number of bits, in which case the digits
+// are taken as a number of that many bits: "0x1F:13" is 0000000011111.
+// Spaces and underscores between digits are ignored.
+func Parse(s string) (*Buffer, error) {
+	return ParseWithBitOrder(s, MSBFirst)
+}
+
+// ParseWithBitOrder works as Parse, returning a buffer using order.
+func ParseWithBitOrder(s string, order BitOrder) (*Buffer, error) {
+	text := strings.TrimSpace(s)
+	numBits := -1
+	if i := strings.LastIndexByte(text, ':'); i >= 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(text[i+1:]))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid number of bits in \"%s\"", s)
+		}
+		numBits = n
+		text = text[:i]
+	}
+	hex := false
+	switch {
+	case strings.HasPrefix(text, "0x"), strings.HasPrefix(text, "0X"):
+		hex = true
+		text = text[2:]
+	case strings.HasPrefix(text, "0b"), strings.HasPrefix(text, "0B"):
+		text = text[2:]
+	}
+	var digits []bool
+	for _, c := range text {
+		var v int
+		switch {
+		case c == ' ' || c == '_' || c == '\t':
+			continue
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		case hex && c >= 'a' && c <= 'f':
+			v = int(c-'a') + 10
+		case hex && c >= 'A' && c <= 'F':
+			v = int(c-'A') + 10
+		default:
+			return nil, fmt.Errorf("invalid character '%c' in \"%s\"", c, s)
+		}
+		if !hex {
+			if v > 1 {
+				return nil, fmt.Errorf("invalid character '%c' in \"%s\"", c, s)
+			}
+			digits = append(digits, v == 1)
+			continue
+		}
+		for i := 3; i >= 0; i-- {
+			digits = append(digits, v>>i&1 == 1)
+		}
+	}
+	if numBits < 0 {
+		numBits = len(digits)
+	}
+	// align the digits to the right, the dropped ones must be zero
+	for len(digits) > numBits {
+		if digits[0] {
+			return nil, fmt.Errorf("value \"%s\" doesn't fit in %d bits", s, numBits)
+		}
+		digits = digits[1:]
+	}
+	out := &Buffer{}
+	out.InitWithBitOrder(numBits, order)
+	pad := numBits - len(digits)
+	for i, v := range digits {
+		if v {
+			writeBits(out.buffer, order, pad+i, 1, 1)
+		}
+	}
+	return out, nil
+}
+
+func (f *Buffer) formatBin(prefix bool) string {
+	var sb strings.Builder
+	if prefix {
+		sb.WriteString("0b")
+	}
+	for i := 0; i < f.bitSize; i++ {
+		sb.WriteByte('0' + byte(readBits(f.buffer, f.order, f.offset+i, 1)))
+	}
+	return sb.String()
+}
+
+func (f *Buffer) formatHex(prefix bool, upper bool) string {
+	digits, prefixText := "0123456789abcdef", "0x"
+	if upper {
+		digits, prefixText = "0123456789ABCDEF", "0X"
+	}
+	var sb strings.Builder
+	if prefix {
+		sb.WriteString(prefixText)
+	}
+	// the first digit takes the bits left over
+	pos := 0
+	take := f.bitSize % 4
+	if take == 0 {
+		take = 4
+	}
+	for pos < f.bitSize {
+		v := 0
+		for i := 0; i < take; i++ {
+			v = v<<1 | int(readBits(f.buffer, f.order, f.offset+pos, 1))
+			pos++
+		}
+		sb.WriteByte(digits[v])
+		take = 4
+	}
+	if f.bitSize%4 != 0 {
+		fmt.Fprintf(&sb, ":%d", f.bitSize)
+	}
+	return sb.String()
+}
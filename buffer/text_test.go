@@ -0,0 +1,110 @@
+package buffer
+
+import (
+	"encoding"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ fmt.Formatter = &Buffer{}
+var _ encoding.TextMarshaler = &Buffer{}
+var _ encoding.TextUnmarshaler = &Buffer{}
+
+func Test_Format(t *testing.T) {
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferN([]byte{0xaf, 0x40}, 10))
+	require.Equal(t, "1010111101", fmt.Sprintf("%b", buf))
+	require.Equal(t, "0b1010111101", fmt.Sprintf("%#b", buf))
+	require.Equal(t, "2bd:10", fmt.Sprintf("%x", buf))
+	require.Equal(t, "0X2BD:10", fmt.Sprintf("%#X", buf))
+	require.Equal(t, "0x2bd:10", fmt.Sprintf("%v", buf))
+	require.Equal(t, "0x2bd:10", buf.String())
+	require.Equal(t, "[  0x2bd:10]", fmt.Sprintf("[%10v]", buf))
+	require.Equal(t, "[0x2bd:10  ]", fmt.Sprintf("[%-10v]", buf))
+	require.Equal(t, "%!d(*buffer.Buffer=0x2bd:10)", fmt.Sprintf("%d", buf))
+
+	require.Nil(t, buf.InitFromRawBufferN([]byte{0x12, 0x34}, 16))
+	require.Equal(t, "1234", fmt.Sprintf("%x", buf))
+	view, err := buf.Slice(4, 8)
+	require.Nil(t, err)
+	require.Equal(t, "0x23", fmt.Sprintf("%v", view))
+
+	// text forms follow index order
+	buf.InitWithBitOrder(4, LSBFirst)
+	require.Nil(t, buf.SetBit(0, true))
+	require.Equal(t, "1000", fmt.Sprintf("%b", buf))
+	require.Equal(t, "8", fmt.Sprintf("%x", buf))
+
+	buf.Init(0)
+	require.Equal(t, "", fmt.Sprintf("%b", buf))
+	require.Equal(t, "0x", fmt.Sprintf("%v", buf))
+}
+
+func Test_Parse(t *testing.T) {
+	cases := []struct {
+		text string
+		bits string
+	}{
+		{"0b1010_1111_01", "1010111101"},
+		{"1010 0110", "10100110"},
+		{"0x1F", "00011111"},
+		{"0x1F:13", "0000000011111"},
+		{"0x1f:5", "11111"},
+		{"  0XaB_cD ", "1010101111001101"},
+		{"0b101:5", "00101"},
+		{"0x2bd:10", "1010111101"},
+		{"0x", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		buf, err := Parse(c.text)
+		require.Nil(t, err, c.text)
+		require.Equal(t, c.bits, fmt.Sprintf("%b", buf), c.text)
+		require.Equal(t, MSBFirst, buf.GetBitOrder())
+	}
+
+	for _, text := range []string{"0x1F:4", "0x1G", "102", "0b1:x", "0x1:-1", "1F"} {
+		_, err := Parse(text)
+		require.NotNil(t, err, text)
+	}
+}
+
+func Test_Parse_RoundTrip(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for size := 0; size < 24; size++ {
+			buf := &Buffer{}
+			buf.InitWithBitOrder(size, order)
+			for i := 0; i < size; i += 3 {
+				buf.SetBit(i, true)
+			}
+			for _, format := range []string{"%v", "%#b", "%b", "%#X"} {
+				text := fmt.Sprintf(format, buf)
+				if format == "%b" && size == 0 {
+					continue
+				}
+				parsed, err := ParseWithBitOrder(text, order)
+				require.Nil(t, err, text)
+				require.Equal(t, buf.GetBitSize(), parsed.GetBitSize(), text)
+				require.Equal(t, buf.GetRawCopy(), parsed.GetRawCopy(), text)
+			}
+		}
+	}
+}
+
+func Test_TextMarshaling(t *testing.T) {
+	buf := &Buffer{}
+	require.Nil(t, buf.InitFromRawBufferNWithBitOrder([]byte{0xaf, 0x02}, 10, LSBFirst))
+	text, err := buf.MarshalText()
+	require.Nil(t, err)
+
+	out := &Buffer{}
+	out.InitWithBitOrder(0, LSBFirst)
+	require.Nil(t, out.UnmarshalText(text))
+	require.Equal(t, LSBFirst, out.GetBitOrder())
+	require.Equal(t, 10, out.GetBitSize())
+	require.Equal(t, buf.GetRawCopy(), out.GetRawCopy())
+
+	require.NotNil(t, out.UnmarshalText([]byte("0xZ")))
+}
@@ -0,0 +1,64 @@
+package buffer
+
+// Integer transforms applied before storing values, such as the Gray code
+// of rotary encoders or the zigzag mapping of protobuf. Zigzag is also
+// available as a SignedEncoding.
+
+// GrayEncode returns the reflected binary Gray code of v, in which
+// consecutive values differ in a single bit.
+func GrayEncode(v uint64) uint64 {
+	return v ^ v>>1
+}
+
+// GrayDecode returns the value whose Gray code is g.
+func GrayDecode(g uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		g ^= g >> shift
+	}
+	return g
+}
+
+// ZigZagEncode maps signed values to unsigned ones alternating the sign, so
+// small magnitudes give small values: 0, -1, 1, -2, 2... map to 0, 1, 2, 3,
+// 4...
+func ZigZagEncode(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// ZigZagDecode returns the value whose zigzag mapping is u.
+func ZigZagDecode(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// SetBitsFromUint64Gray stores the Gray code of the size low bits of v.
+func (f *Buffer) SetBitsFromUint64Gray(idx int, v uint64, size int) error {
+	if size < 64 {
+		v &= 1<<size - 1
+	}
+	return f.SetBitsFromUint64(idx, GrayEncode(v), size)
+}
+
+// GetBitsToUint64Gray decodes the Gray code held by the size bits at idx.
+func (f *Buffer) GetBitsToUint64Gray(idx int, size int) (uint64, error) {
+	g, err := f.GetBitsToUint64(idx, size)
+	if err != nil {
+		return 0, err
+	}
+	return GrayDecode(g), nil
+}
+
+// SetBitsFromInt64ZigZag stores the zigzag mapping of v in size bits. It is
+// the same as SetBitsFromInt64WithEncoding using ZigZag.
+func (f *Buffer) SetBitsFromInt64ZigZag(idx int, v int64, size int) error {
+	return f.SetBitsFromUint64(idx, ZigZagEncode(v), size)
+}
+
+// GetBitsToInt64ZigZag decodes the zigzag mapped value held by the size bits
+// at idx.
+func (f *Buffer) GetBitsToInt64ZigZag(idx int, size int) (int64, error) {
+	u, err := f.GetBitsToUint64(idx, size)
+	if err != nil {
+		return 0, err
+	}
+	return ZigZagDecode(u), nil
+}
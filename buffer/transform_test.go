@@ -0,0 +1,71 @@
+package buffer
+
+import (
+	"math"
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Gray(t *testing.T) {
+	expected := []uint64{0, 1, 3, 2, 6, 7, 5, 4, 12, 13, 15, 14, 10, 11, 9, 8}
+	for v, g := range expected {
+		require.Equal(t, g, GrayEncode(uint64(v)))
+		require.Equal(t, uint64(v), GrayDecode(g))
+	}
+	for _, v := range []uint64{1000, 1 << 40, math.MaxUint64, math.MaxUint64 - 1} {
+		require.Equal(t, v, GrayDecode(GrayEncode(v)))
+		// consecutive values differ in one bit
+		require.Equal(t, 1, bits.OnesCount64(GrayEncode(v)^GrayEncode(v+1)))
+	}
+}
+
+func Test_ZigZag(t *testing.T) {
+	cases := []struct {
+		v int64
+		u uint64
+	}{
+		{0, 0}, {-1, 1}, {1, 2}, {-2, 3}, {2, 4}, {-64, 127},
+		{math.MaxInt64, math.MaxUint64 - 1}, {math.MinInt64, math.MaxUint64},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.u, ZigZagEncode(c.v))
+		require.Equal(t, c.v, ZigZagDecode(c.u))
+	}
+}
+
+func Test_GrayZigZagAccessors(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(16, order)
+		for v := uint64(0); v < 1<<10; v++ {
+			require.Nil(t, buf.SetBitsFromUint64Gray(3, v, 10))
+			raw, _ := buf.GetBitsToUint64(3, 10)
+			require.Equal(t, GrayEncode(v), raw)
+			out, err := buf.GetBitsToUint64Gray(3, 10)
+			require.Nil(t, err)
+			require.Equal(t, v, out)
+		}
+		for v := int64(-512); v < 512; v++ {
+			require.Nil(t, buf.SetBitsFromInt64ZigZag(3, v, 10))
+			out, err := buf.GetBitsToInt64ZigZag(3, 10)
+			require.Nil(t, err)
+			require.Equal(t, v, out)
+			enc, err := buf.GetBitsToInt64WithEncoding(3, 10, ZigZag)
+			require.Nil(t, err)
+			require.Equal(t, v, enc)
+		}
+	}
+
+	// values wider than size keep the code of their low bits
+	buf := &Buffer{}
+	buf.Init(4)
+	require.Nil(t, buf.SetBitsFromUint64Gray(0, 0x1c, 4))
+	v, _ := buf.GetBitsToUint64Gray(0, 4)
+	require.Equal(t, uint64(0xc), v)
+	_, err := buf.GetBitsToUint64Gray(1, 4)
+	require.NotNil(t, err)
+	_, err = buf.GetBitsToInt64ZigZag(0, 5)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,378 @@
+package buffer
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Variable length integer codes. Codewords are bit sequences laid out in
+// index order whatever the bit order of the buffer is; the multi-bit parts of
+// a codeword are written most significant bit first, as in H.264. LEB128
+// groups are written as 8-bit values, so byte aligned LEB128 codes produce
+// the standard byte sequence in both bit orders.
+//
+// Every Set function returns the number of bits written and every Get
+// function the number of bits consumed. The BitWriter and BitReader
+// counterparts work at the cursor, growing the buffer when writing.
+
+// fibonacci holds the Fibonacci numbers F(2), F(3)... that fit in an uint64.
+var fibonacci = func() []uint64 {
+	fib := []uint64{1, 2}
+	for {
+		a, b := fib[len(fib)-2], fib[len(fib)-1]
+		if a > math.MaxUint64-b {
+			return fib
+		}
+		fib = append(fib, a+b)
+	}
+}()
+
+func (f *Buffer) SetUExpGolomb(idx int, v uint64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteUExpGolomb(v) })
+}
+
+func (f *Buffer) GetUExpGolomb(idx int) (v uint64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadUExpGolomb(); return })
+	return
+}
+
+func (f *Buffer) SetSExpGolomb(idx int, v int64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteSExpGolomb(v) })
+}
+
+func (f *Buffer) GetSExpGolomb(idx int) (v int64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadSExpGolomb(); return })
+	return
+}
+
+func (f *Buffer) SetEliasGamma(idx int, v uint64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteEliasGamma(v) })
+}
+
+func (f *Buffer) GetEliasGamma(idx int) (v uint64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadEliasGamma(); return })
+	return
+}
+
+func (f *Buffer) SetEliasDelta(idx int, v uint64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteEliasDelta(v) })
+}
+
+func (f *Buffer) GetEliasDelta(idx int) (v uint64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadEliasDelta(); return })
+	return
+}
+
+func (f *Buffer) SetFibonacci(idx int, v uint64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteFibonacci(v) })
+}
+
+func (f *Buffer) GetFibonacci(idx int) (v uint64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadFibonacci(); return })
+	return
+}
+
+func (f *Buffer) SetLEB128(idx int, v uint64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteLEB128(v) })
+}
+
+func (f *Buffer) GetLEB128(idx int) (v uint64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadLEB128(); return })
+	return
+}
+
+func (f *Buffer) SetSLEB128(idx int, v int64) (int, error) {
+	return f.setCode(idx, func(w *BitWriter) error { return w.WriteSLEB128(v) })
+}
+
+func (f *Buffer) GetSLEB128(idx int) (v int64, n int, err error) {
+	n, err = f.getCode(idx, func(r *BitReader) (err error) { v, err = r.ReadSLEB128(); return })
+	return
+}
+
+// setCode encodes a codeword into a scratch buffer and copies it at idx.
+func (f *Buffer) setCode(reqidx int, encode func(w *BitWriter) error) (n int, err error) {
+	code := &Buffer{}
+	code.InitWithBitOrder(0, f.order)
+	if err = encode(NewBitWriter(code)); err != nil {
+		return 0, err
+	}
+	if err = f.SetBitsFromRawBuffer(reqidx, code.buffer, code.bitSize); err != nil {
+		return 0, err
+	}
+	return code.bitSize, nil
+}
+
+func (f *Buffer) getCode(reqidx int, decode func(r *BitReader) error) (n int, err error) {
+	var idx int
+	if idx, err = f.parseParams(reqidx, 1, -1); err != nil {
+		return 0, err
+	}
+	r := NewBitReader(f)
+	r.pos = idx
+	if err = decode(r); err != nil {
+		return 0, err
+	}
+	return r.pos - idx, nil
+}
+
+// WriteUExpGolomb writes v as an unsigned Exp-Golomb code, ue(v).
+func (w *BitWriter) WriteUExpGolomb(v uint64) error {
+	x := v + 1
+	if x == 0 {
+		// v+1 needs 65 bits
+		if err := w.writeSeq(0, 64); err != nil {
+			return err
+		}
+		if err := w.WriteBool(true); err != nil {
+			return err
+		}
+		return w.writeSeq(0, 64)
+	}
+	return w.writePrefixed(x)
+}
+
+// WriteSExpGolomb writes v as a signed Exp-Golomb code, se(v).
+func (w *BitWriter) WriteSExpGolomb(v int64) error {
+	if v == math.MinInt64 {
+		return fmt.Errorf("value %d out of range for se(v)", v)
+	}
+	if v > 0 {
+		return w.WriteUExpGolomb(uint64(v)*2 - 1)
+	}
+	return w.WriteUExpGolomb(uint64(-v) * 2)
+}
+
+// WriteEliasGamma writes v (>= 1) as an Elias gamma code.
+func (w *BitWriter) WriteEliasGamma(v uint64) error {
+	if v == 0 {
+		return fmt.Errorf("Elias gamma can't encode 0")
+	}
+	return w.writePrefixed(v)
+}
+
+// WriteEliasDelta writes v (>= 1) as an Elias delta code.
+func (w *BitWriter) WriteEliasDelta(v uint64) error {
+	if v == 0 {
+		return fmt.Errorf("Elias delta can't encode 0")
+	}
+	numBits := bits.Len64(v)
+	if err := w.WriteEliasGamma(uint64(numBits)); err != nil {
+		return err
+	}
+	return w.writeSeq(v, numBits-1)
+}
+
+// WriteFibonacci writes v (>= 1) as a Fibonacci code.
+func (w *BitWriter) WriteFibonacci(v uint64) error {
+	if v == 0 {
+		return fmt.Errorf("Fibonacci coding can't encode 0")
+	}
+	top := len(fibonacci) - 1
+	for fibonacci[top] > v {
+		top--
+	}
+	code := make([]bool, top+1)
+	for i := top; i >= 0 && v > 0; i-- {
+		if fibonacci[i] <= v {
+			code[i] = true
+			v -= fibonacci[i]
+		}
+	}
+	for _, b := range code {
+		if err := w.WriteBool(b); err != nil {
+			return err
+		}
+	}
+	return w.WriteBool(true)
+}
+
+// WriteLEB128 writes v as an unsigned LEB128 code.
+func (w *BitWriter) WriteLEB128(v uint64) error {
+	for {
+		group := v & 0x7f
+		v >>= 7
+		if v != 0 {
+			group |= 0x80
+		}
+		if err := w.WriteUint(group, 8); err != nil {
+			return err
+		}
+		if v == 0 {
+			return nil
+		}
+	}
+}
+
+// WriteSLEB128 writes v as a signed LEB128 code.
+func (w *BitWriter) WriteSLEB128(v int64) error {
+	for {
+		group := uint64(v & 0x7f)
+		v >>= 7
+		last := (v == 0 && group&0x40 == 0) || (v == -1 && group&0x40 != 0)
+		if !last {
+			group |= 0x80
+		}
+		if err := w.WriteUint(group, 8); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// writePrefixed writes x (> 0) preceded by as many zeros as bits follow its
+// most significant one.
+func (w *BitWriter) writePrefixed(x uint64) error {
+	numBits := bits.Len64(x)
+	if err := w.writeSeq(0, numBits-1); err != nil {
+		return err
+	}
+	return w.writeSeq(x, numBits)
+}
+
+// writeSeq writes the numBits low bits of v, most significant first, in
+// index order.
+func (w *BitWriter) writeSeq(v uint64, numBits int) error {
+	if w.buf.order == LSBFirst && numBits > 0 {
+		v = bits.Reverse64(v) >> (64 - numBits)
+	}
+	return w.WriteUint(v, numBits)
+}
+
+func (r *BitReader) ReadUExpGolomb() (uint64, error) {
+	zeros, err := r.readZeros(64)
+	if err != nil {
+		return 0, err
+	}
+	info, err := r.readSeq(zeros)
+	if err != nil {
+		return 0, err
+	}
+	if zeros == 64 && info != 0 {
+		return 0, fmt.Errorf("Exp-Golomb code out of range")
+	}
+	return info + (uint64(1) << zeros) - 1, nil
+}
+
+func (r *BitReader) ReadSExpGolomb() (int64, error) {
+	k, err := r.ReadUExpGolomb()
+	if err != nil {
+		return 0, err
+	}
+	if k&1 != 0 {
+		return int64(k/2) + 1, nil
+	}
+	return -int64(k / 2), nil
+}
+
+func (r *BitReader) ReadEliasGamma() (uint64, error) {
+	zeros, err := r.readZeros(63)
+	if err != nil {
+		return 0, err
+	}
+	info, err := r.readSeq(zeros)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(1)<<zeros | info, nil
+}
+
+func (r *BitReader) ReadEliasDelta() (uint64, error) {
+	numBits, err := r.ReadEliasGamma()
+	if err != nil {
+		return 0, err
+	}
+	if numBits > 64 {
+		return 0, fmt.Errorf("Elias delta code out of range")
+	}
+	info, err := r.readSeq(int(numBits) - 1)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(1)<<(numBits-1) | info, nil
+}
+
+func (r *BitReader) ReadFibonacci() (uint64, error) {
+	var v uint64
+	prev := false
+	for i := 0; ; i++ {
+		b, err := r.ReadBool()
+		if err != nil {
+			return 0, err
+		}
+		if b && prev {
+			return v, nil
+		}
+		if b {
+			if i >= len(fibonacci) || v > math.MaxUint64-fibonacci[i] {
+				return 0, fmt.Errorf("Fibonacci code out of range")
+			}
+			v += fibonacci[i]
+		}
+		prev = b
+	}
+}
+
+func (r *BitReader) ReadLEB128() (uint64, error) {
+	var v uint64
+	for shift := 0; ; shift += 7 {
+		group, err := r.ReadUint(8)
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 64 || (shift == 63 && group&0x7e != 0) {
+			return 0, fmt.Errorf("LEB128 code out of range")
+		}
+		v |= (group & 0x7f) << shift
+		if group&0x80 == 0 {
+			return v, nil
+		}
+	}
+}
+
+func (r *BitReader) ReadSLEB128() (int64, error) {
+	var v int64
+	for shift := 0; ; shift += 7 {
+		group, err := r.ReadUint(8)
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 64 {
+			return 0, fmt.Errorf("LEB128 code out of range")
+		}
+		v |= int64(group&0x7f) << shift
+		if group&0x80 == 0 {
+			if shift+7 < 64 && group&0x40 != 0 {
+				v |= -1 << (shift + 7)
+			}
+			return v, nil
+		}
+	}
+}
+
+// readZeros consumes the zeros preceding the next one, and the one. It fails
+// if more than max zeros are found.
+func (r *BitReader) readZeros(max int) (int, error) {
+	for zeros := 0; zeros <= max; zeros++ {
+		b, err := r.ReadBool()
+		if err != nil {
+			return 0, err
+		}
+		if b {
+			return zeros, nil
+		}
+	}
+	return 0, fmt.Errorf("too many leading zeros (max: %d)", max)
+}
+
+// readSeq reads numBits bits, the first one as the most significant.
+func (r *BitReader) readSeq(numBits int) (uint64, error) {
+	v, err := r.ReadUint(numBits)
+	if err == nil && r.buf.order == LSBFirst && numBits > 0 {
+		v = bits.Reverse64(v) >> (64 - numBits)
+	}
+	return v, err
+}
@@ -0,0 +1,164 @@
+package buffer
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bitString(b *Buffer, idx int, n int) string {
+	s := ""
+	for i := idx; i < idx+n; i++ {
+		if v, _ := b.GetBit(i); v {
+			s += "1"
+		} else {
+			s += "0"
+		}
+	}
+	return s
+}
+
+func Test_ExpGolomb_Codewords(t *testing.T) {
+	cases := []struct {
+		v    uint64
+		code string
+	}{
+		{0, "1"}, {1, "010"}, {2, "011"}, {3, "00100"}, {8, "0001001"},
+	}
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(20, order)
+		for _, c := range cases {
+			buf.UnsetAll()
+			n, err := buf.SetUExpGolomb(3, c.v)
+			require.Nil(t, err)
+			require.Equal(t, len(c.code), n)
+			require.Equal(t, c.code, bitString(buf, 3, n))
+			v, n, err := buf.GetUExpGolomb(3)
+			require.Nil(t, err)
+			require.Equal(t, len(c.code), n)
+			require.Equal(t, c.v, v)
+		}
+	}
+
+	signed := map[int64]string{0: "1", 1: "010", -1: "011", 2: "00100", -2: "00101"}
+	buf := &Buffer{}
+	buf.Init(16)
+	for v, code := range signed {
+		n, err := buf.SetSExpGolomb(5, v)
+		require.Nil(t, err)
+		require.Equal(t, code, bitString(buf, 5, n))
+		got, n2, err := buf.GetSExpGolomb(5)
+		require.Nil(t, err)
+		require.Equal(t, n, n2)
+		require.Equal(t, v, got)
+	}
+}
+
+func Test_Elias_Fibonacci_Codewords(t *testing.T) {
+	buf := &Buffer{}
+	buf.Init(32)
+	n, err := buf.SetEliasGamma(1, 9)
+	require.Nil(t, err)
+	require.Equal(t, "0001001", bitString(buf, 1, n))
+	n, err = buf.SetEliasDelta(1, 10)
+	require.Nil(t, err)
+	require.Equal(t, "00100010", bitString(buf, 1, n))
+	n, err = buf.SetFibonacci(1, 11)
+	require.Nil(t, err)
+	require.Equal(t, "001011", bitString(buf, 1, n))
+	v, n, err := buf.GetFibonacci(1)
+	require.Nil(t, err)
+	require.Equal(t, 6, n)
+	require.Equal(t, uint64(11), v)
+
+	_, err = buf.SetEliasGamma(0, 0)
+	require.NotNil(t, err)
+	_, err = buf.SetFibonacci(0, 0)
+	require.NotNil(t, err)
+	_, err = buf.SetEliasGamma(0, math.MaxUint64)
+	require.NotNil(t, err)
+}
+
+func Test_LEB128_Bytes(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(32, order)
+		n, err := buf.SetLEB128(0, 624485)
+		require.Nil(t, err)
+		require.Equal(t, 24, n)
+		require.Equal(t, []byte{0xe5, 0x8e, 0x26}, buf.GetRawBuffer()[:3])
+		n, err = buf.SetSLEB128(0, -123456)
+		require.Nil(t, err)
+		require.Equal(t, 24, n)
+		require.Equal(t, []byte{0xc0, 0xbb, 0x78}, buf.GetRawBuffer()[:3])
+		v, n, err := buf.GetSLEB128(0)
+		require.Nil(t, err)
+		require.Equal(t, 24, n)
+		require.Equal(t, int64(-123456), v)
+	}
+}
+
+func Test_VLC_RoundTrip(t *testing.T) {
+	unsigned := []uint64{1, 2, 3, 7, 100, 1 << 20, 1<<63 - 1, 1 << 63, math.MaxUint64}
+	signed := []int64{0, 1, -1, 63, -64, 64, -65, 1 << 40, math.MaxInt64, math.MinInt64 + 1}
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		buf := &Buffer{}
+		buf.InitWithBitOrder(0, order)
+		w := NewBitWriter(buf)
+		require.Nil(t, w.WriteUint(0x5, 3))
+		for _, v := range unsigned {
+			require.Nil(t, w.WriteUExpGolomb(v))
+			require.Nil(t, w.WriteEliasGamma(v))
+			require.Nil(t, w.WriteEliasDelta(v))
+			require.Nil(t, w.WriteFibonacci(v))
+			require.Nil(t, w.WriteLEB128(v))
+		}
+		for _, v := range signed {
+			require.Nil(t, w.WriteSExpGolomb(v))
+			require.Nil(t, w.WriteSLEB128(v))
+		}
+		r := NewBitReader(buf)
+		require.Nil(t, r.Skip(3))
+		for _, v := range unsigned {
+			got, err := r.ReadUExpGolomb()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+			got, err = r.ReadEliasGamma()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+			got, err = r.ReadEliasDelta()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+			got, err = r.ReadFibonacci()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+			got, err = r.ReadLEB128()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+		}
+		for _, v := range signed {
+			got, err := r.ReadSExpGolomb()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+			got, err = r.ReadSLEB128()
+			require.Nil(t, err)
+			require.Equal(t, v, got)
+		}
+		require.Equal(t, 0, r.Remaining())
+	}
+}
+
+func Test_VLC_Errors(t *testing.T) {
+	buf := &Buffer{}
+	buf.InitFromRawBufferN([]byte{0x00, 0x00}, 12)
+	_, _, err := buf.GetUExpGolomb(0)
+	require.Equal(t, io.EOF, err)
+	_, err = buf.SetUExpGolomb(0, 1<<10)
+	require.NotNil(t, err)
+	buf.InitFromRawBuffer([]byte{0xff})
+	_, _, err = buf.GetLEB128(0)
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,187 @@
+package crc
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// Params describes a CRC using the Rocksoft model, as in the catalogue of
+// parametrised CRC algorithms. Poly is written without the top bit and
+// Check is the CRC of the ASCII string "123456789".
+//
+// Data is processed in groups of 8 bits counted from the start of the range,
+// each group read as GetBitsToUint64 does. Groups are fed most significant
+// bit first, or least significant bit first when RefIn is set; a trailing
+// group of less than 8 bits is fed the same way. So ranges of MSBFirst
+// buffers are processed in index order when RefIn is false, and ranges of
+// LSBFirst buffers when RefIn is true.
+type Params struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Poly   uint64 `json:"poly"`
+	Init   uint64 `json:"init"`
+	RefIn  bool   `json:"refIn"`
+	RefOut bool   `json:"refOut"`
+	XorOut uint64 `json:"xorOut"`
+	Check  uint64 `json:"check"`
+}
+
+var (
+	CRC8SMBUS       = Params{Name: "CRC-8/SMBUS", Width: 8, Poly: 0x07, Check: 0xf4}
+	CRC15CAN        = Params{Name: "CRC-15/CAN", Width: 15, Poly: 0x4599, Check: 0x059e}
+	CRC16CCITT      = Params{Name: "CRC-16/CCITT", Width: 16, Poly: 0x1021, RefIn: true, RefOut: true, Check: 0x2189}
+	CRC16CCITTFalse = Params{Name: "CRC-16/CCITT-FALSE", Width: 16, Poly: 0x1021, Init: 0xffff, Check: 0x29b1}
+	CRC16MODBUS     = Params{Name: "CRC-16/MODBUS", Width: 16, Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, Check: 0x4b37}
+	CRC24ADSB       = Params{Name: "CRC-24/ADSB", Width: 24, Poly: 0xfff409, Check: 0x054268}
+	CRC32           = Params{Name: "CRC-32", Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xcbf43926}
+	CRC32C          = Params{Name: "CRC-32C", Width: 32, Poly: 0x1edc6f41, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xe3069283}
+	CRC64XZ         = Params{Name: "CRC-64/XZ", Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff, Check: 0x995dc9bbdf1939fa}
+)
+
+// Presets holds the predefined CRCs.
+var Presets = []Params{CRC8SMBUS, CRC15CAN, CRC16CCITT, CRC16CCITTFalse, CRC16MODBUS, CRC24ADSB, CRC32, CRC32C, CRC64XZ}
+
+// Preset returns the predefined CRC called name (case insensitive).
+func Preset(name string) (Params, error) {
+	for _, p := range Presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Params{}, fmt.Errorf("unknown CRC \"%s\"", name)
+}
+
+// CRC computes a CRC. The register is kept aligned to the bit that enters
+// first: the top of the uint64 for non reflected CRCs and the bottom for
+// reflected ones, so one table works for every width.
+type CRC struct {
+	params Params
+	poly   uint64
+	init   uint64
+	table  [256]uint64
+}
+
+func New(p Params) (*CRC, error) {
+	if p.Width < 1 || p.Width > 64 {
+		return nil, fmt.Errorf("invalid CRC width (%d). Must be 1..64", p.Width)
+	}
+	mask := ^uint64(0) >> (64 - p.Width)
+	if p.Poly&^mask != 0 || p.Init&^mask != 0 || p.XorOut&^mask != 0 {
+		return nil, fmt.Errorf("CRC parameters wider than %d bits", p.Width)
+	}
+	c := &CRC{params: p}
+	if p.RefIn {
+		c.poly = reflect(p.Poly, p.Width)
+		c.init = reflect(p.Init, p.Width)
+	} else {
+		c.poly = p.Poly << (64 - p.Width)
+		c.init = p.Init << (64 - p.Width)
+	}
+	for i := range c.table {
+		reg := uint64(i)
+		if !p.RefIn {
+			reg <<= 56
+		}
+		c.table[i] = c.updateBits(reg, 0, 8)
+	}
+	return c, nil
+}
+
+// NewPreset returns the CRC of the preset called name.
+func NewPreset(name string) (*CRC, error) {
+	p, err := Preset(name)
+	if err != nil {
+		return nil, err
+	}
+	return New(p)
+}
+
+func (c *CRC) Params() Params {
+	return c.params
+}
+
+// Checksum returns the CRC of the numBits bits of buf starting at idx.
+func (c *CRC) Checksum(buf *buffer.Buffer, idx int, numBits int) (uint64, error) {
+	data, err := buf.Slice(idx, numBits)
+	if err != nil {
+		return 0, err
+	}
+	numBytes := numBits / 8
+	raw, err := data.GetBitsToRawBuffer(0, numBytes*8)
+	if err != nil {
+		return 0, err
+	}
+	// whole bytes read as uint64 match the raw bytes in both bit orders
+	reg := c.updateBytes(c.init, raw)
+	if tail := numBits - numBytes*8; tail > 0 {
+		v, err := data.GetBitsToUint64(numBytes*8, tail)
+		if err != nil {
+			return 0, err
+		}
+		reg = c.updateBits(reg, v, tail)
+	}
+	return c.finish(reg), nil
+}
+
+// ChecksumBytes returns the CRC of data.
+func (c *CRC) ChecksumBytes(data []byte) uint64 {
+	return c.finish(c.updateBytes(c.init, data))
+}
+
+func (c *CRC) updateBytes(reg uint64, data []byte) uint64 {
+	if c.params.RefIn {
+		for _, b := range data {
+			reg = reg>>8 ^ c.table[byte(reg)^b]
+		}
+		return reg
+	}
+	for _, b := range data {
+		reg = reg<<8 ^ c.table[byte(reg>>56)^b]
+	}
+	return reg
+}
+
+// updateBits feeds the n (0..8) low bits of v.
+func (c *CRC) updateBits(reg uint64, v uint64, n int) uint64 {
+	if c.params.RefIn {
+		reg ^= v
+		for i := 0; i < n; i++ {
+			if reg&1 != 0 {
+				reg = reg>>1 ^ c.poly
+			} else {
+				reg >>= 1
+			}
+		}
+		return reg
+	}
+	reg ^= v << (64 - n)
+	for i := 0; i < n; i++ {
+		if reg&(1<<63) != 0 {
+			reg = reg<<1 ^ c.poly
+		} else {
+			reg <<= 1
+		}
+	}
+	return reg
+}
+
+func (c *CRC) finish(reg uint64) uint64 {
+	w := c.params.Width
+	if c.params.RefIn {
+		reg = reflect(reg, w)
+	} else {
+		reg >>= 64 - w
+	}
+	if c.params.RefOut {
+		reg = reflect(reg, w)
+	}
+	return reg ^ c.params.XorOut
+}
+
+// reflect reverses the w low bits of v.
+func reflect(v uint64, w int) uint64 {
+	return bits.Reverse64(v) >> (64 - w)
+}
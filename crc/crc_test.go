@@ -0,0 +1,146 @@
+package crc
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nayarsystems/buffer/buffer"
+	"github.com/stretchr/testify/require"
+)
+
+var checkInput = []byte("123456789")
+
+// refChecksum is a straightforward bit by bit implementation of the model
+// described in Params.
+func refChecksum(p Params, buf *buffer.Buffer, idx int, numBits int) uint64 {
+	top := uint64(1) << (p.Width - 1)
+	mask := top | (top - 1)
+	reg := p.Init
+	for pos := 0; pos < numBits; pos += 8 {
+		n := numBits - pos
+		if n > 8 {
+			n = 8
+		}
+		v, _ := buf.GetBitsToUint64(idx+pos, n)
+		for i := 0; i < n; i++ {
+			var bit uint64
+			if p.RefIn {
+				bit = v >> i & 1
+			} else {
+				bit = v >> (n - 1 - i) & 1
+			}
+			fb := (reg&top != 0) != (bit != 0)
+			reg = reg << 1 & mask
+			if fb {
+				reg ^= p.Poly
+			}
+		}
+	}
+	if p.RefOut {
+		reg = reflect(reg, p.Width)
+	}
+	return reg ^ p.XorOut
+}
+
+func Test_Presets_CheckValue(t *testing.T) {
+	for _, p := range Presets {
+		c, err := New(p)
+		require.Nil(t, err)
+		require.Equal(t, p.Check, c.ChecksumBytes(checkInput), p.Name)
+		for _, order := range []buffer.BitOrder{buffer.MSBFirst, buffer.LSBFirst} {
+			for idx := 0; idx < 8; idx++ {
+				buf := &buffer.Buffer{}
+				buf.InitWithBitOrder(idx+len(checkInput)*8+5, order)
+				require.Nil(t, buf.SetBitsFromRawBuffer(idx, checkInput, len(checkInput)*8))
+				v, err := c.Checksum(buf, idx, len(checkInput)*8)
+				require.Nil(t, err)
+				require.Equal(t, p.Check, v, "%s %v idx: %d", p.Name, order, idx)
+			}
+		}
+	}
+}
+
+func Test_Preset(t *testing.T) {
+	p, err := Preset("crc-32c")
+	require.Nil(t, err)
+	require.Equal(t, CRC32C, p)
+	c, err := NewPreset("CRC-16/MODBUS")
+	require.Nil(t, err)
+	require.Equal(t, CRC16MODBUS, c.Params())
+	_, err = NewPreset("CRC-7/UNKNOWN")
+	require.NotNil(t, err)
+}
+
+func Test_New_InvalidParams(t *testing.T) {
+	_, err := New(Params{Width: 0, Poly: 1})
+	require.NotNil(t, err)
+	_, err = New(Params{Width: 65, Poly: 1})
+	require.NotNil(t, err)
+	_, err = New(Params{Width: 4, Poly: 0x13})
+	require.NotNil(t, err)
+}
+
+func Test_Checksum_MatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(17))
+	for i := 0; i < 500; i++ {
+		p := Params{Width: 1 + rnd.Intn(64), RefIn: rnd.Intn(2) == 0, RefOut: rnd.Intn(2) == 0}
+		mask := ^uint64(0) >> (64 - p.Width)
+		p.Poly = rnd.Uint64()&mask | 1
+		p.Init = rnd.Uint64() & mask
+		p.XorOut = rnd.Uint64() & mask
+		c, err := New(p)
+		require.Nil(t, err)
+
+		order := buffer.MSBFirst
+		if rnd.Intn(2) == 0 {
+			order = buffer.LSBFirst
+		}
+		raw := make([]byte, 40)
+		rnd.Read(raw)
+		buf := &buffer.Buffer{}
+		buf.InitFromRawBufferWithBitOrder(raw, order)
+		idx := rnd.Intn(40)
+		numBits := rnd.Intn(len(raw)*8 - idx)
+		v, err := c.Checksum(buf, idx, numBits)
+		require.Nil(t, err)
+		require.Equal(t, refChecksum(p, buf, idx, numBits), v, "%+v %v idx: %d bits: %d", p, order, idx, numBits)
+	}
+}
+
+func Test_Checksum_CANResidue(t *testing.T) {
+	// a message followed by its CRC has a zero CRC
+	c, err := New(CRC15CAN)
+	require.Nil(t, err)
+	rnd := rand.New(rand.NewSource(15))
+	for i := 0; i < 20; i++ {
+		buf := &buffer.Buffer{}
+		buf.Init(3 + 83 + 15)
+		for j := 0; j < 83; j++ {
+			require.Nil(t, buf.SetBit(3+j, rnd.Intn(2) == 0))
+		}
+		v, err := c.Checksum(buf, 3, 83)
+		require.Nil(t, err)
+		require.Nil(t, buf.SetBitsFromUint64(3+83, v, 15))
+		v, err = c.Checksum(buf, 3, 83+15)
+		require.Nil(t, err)
+		require.Equal(t, uint64(0), v)
+	}
+}
+
+func Test_Checksum_OutOfRange(t *testing.T) {
+	c, _ := New(CRC8SMBUS)
+	buf := &buffer.Buffer{}
+	buf.Init(16)
+	_, err := c.Checksum(buf, 4, 16)
+	require.NotNil(t, err)
+}
+
+func Benchmark_Checksum_CRC32(b *testing.B) {
+	c, _ := New(CRC32)
+	buf := &buffer.Buffer{}
+	buf.Init(8 * 4096)
+	b.SetBytes(4096)
+	for i := 0; i < b.N; i++ {
+		c.Checksum(buf, 0, 8*4096)
+	}
+}
@@ -0,0 +1,165 @@
+package frame
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// BCDDesc turns a field into a decimal number encoded as BCD. The number of
+// digits is derived from the field size, which must be a multiple of 4 bits
+// (8 for unpacked BCD, with at least 8 bits for packed decimal).
+//
+// The field keeps the type of its default value: a string (zero padded to
+// the number of digits, "-" prefixed for negative packed decimal values) or
+// any integer type. Either way it can be Set from strings and integers.
+type BCDDesc struct {
+	Format buffer.BCDFormat `json:"format"`
+}
+
+func (d *BCDDesc) digits(size int) (int, error) {
+	switch d.Format {
+	case buffer.BCDPacked:
+		if size > 0 && size%4 == 0 {
+			return size / 4, nil
+		}
+	case buffer.BCDUnpacked:
+		if size > 0 && size%8 == 0 {
+			return size / 8, nil
+		}
+	case buffer.BCDPackedDecimal:
+		if size > 4 && size%4 == 0 {
+			return size/4 - 1, nil
+		}
+	default:
+		return 0, fmt.Errorf("invalid BCD format (%d)", int(d.Format))
+	}
+	return 0, fmt.Errorf("invalid size value (%d) for %v", size, d.Format)
+}
+
+// value converts v to the type of current after checking that it fits in
+// the field.
+func (d *BCDDesc) value(field *field, current interface{}, v interface{}) (interface{}, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case int8, int16, int, int32, int64:
+		s = fmt.Sprint(v)
+	case uint8, uint16, uint, uint32, uint64:
+		s = fmt.Sprint(v)
+	default:
+		return nil, fmt.Errorf("can't set BCD field '%s' to %v (%T)", field.name, v, v)
+	}
+	digits, err := d.digits(field.size)
+	if err != nil {
+		return nil, err
+	}
+	// check the value by encoding it
+	scratch := &buffer.Buffer{}
+	scratch.Init(field.size)
+	if err = scratch.SetBCDFromString(0, s, digits, d.Format); err != nil {
+		return nil, fmt.Errorf("can't set BCD field '%s': %w", field.name, err)
+	}
+	s, _ = scratch.GetBCDToString(0, digits, d.Format)
+	return d.fromString(field, current, s)
+}
+
+// fromString converts the canonical digit string s to the type of current.
+func (d *BCDDesc) fromString(field *field, current interface{}, s string) (interface{}, error) {
+	var err error
+	var out interface{}
+	switch current.(type) {
+	case string:
+		return s, nil
+	case int8, int16, int, int32, int64:
+		var v int64
+		if v, err = strconv.ParseInt(s, 10, 64); err == nil {
+			out, err = convertInt(current, v)
+		}
+	case uint8, uint16, uint, uint32, uint64:
+		if strings.HasPrefix(s, "-") {
+			return nil, fmt.Errorf("negative value %s for unsigned BCD field '%s'", s, field.name)
+		}
+		var v uint64
+		if v, err = strconv.ParseUint(s, 10, 64); err == nil {
+			out, err = convertUint(current, v)
+		}
+	default:
+		return nil, fmt.Errorf("invalid type (%T) for BCD field '%s'", current, field.name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("value %s out of range for BCD field '%s' (%T)", s, field.name, current)
+	}
+	return out, nil
+}
+
+func (d *BCDDesc) encode(buf *buffer.Buffer, field *field, v interface{}) error {
+	digits, err := d.digits(field.size)
+	if err != nil {
+		return err
+	}
+	return buf.SetBCDFromString(field.offset, fmt.Sprint(v), digits, d.Format)
+}
+
+func (d *BCDDesc) decode(buf *buffer.Buffer, field *field, current interface{}) (interface{}, error) {
+	digits, err := d.digits(field.size)
+	if err != nil {
+		return nil, err
+	}
+	s, err := buf.GetBCDToString(field.offset, digits, d.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for BCD field '%s': %w", field.name, err)
+	}
+	return d.fromString(field, current, s)
+}
+
+func convertInt(current interface{}, v int64) (interface{}, error) {
+	switch current.(type) {
+	case int8:
+		if v == int64(int8(v)) {
+			return int8(v), nil
+		}
+	case int16:
+		if v == int64(int16(v)) {
+			return int16(v), nil
+		}
+	case int32:
+		if v == int64(int32(v)) {
+			return int32(v), nil
+		}
+	case int:
+		if v == int64(int(v)) {
+			return int(v), nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("out of range")
+}
+
+func convertUint(current interface{}, v uint64) (interface{}, error) {
+	switch current.(type) {
+	case uint8:
+		if v == uint64(uint8(v)) {
+			return uint8(v), nil
+		}
+	case uint16:
+		if v == uint64(uint16(v)) {
+			return uint16(v), nil
+		}
+	case uint32:
+		if v == uint64(uint32(v)) {
+			return uint32(v), nil
+		}
+	case uint:
+		if v == uint64(uint(v)) {
+			return uint(v), nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("out of range")
+}
@@ -0,0 +1,45 @@
+package frame
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/jaracil/ei"
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// isBigIntField reports whether the field holds a *big.Int, which allows
+// integers of any size such as 96-bit serial numbers.
+func isBigIntField(field *field) bool {
+	_, ok := field.defaultValue.(*big.Int)
+	return ok
+}
+
+// bigValue converts v to the value stored by a big integer field, applying
+// its overflow mode. Strings may use base prefixes (0x, 0b, 0o).
+func bigValue(field *field, v interface{}) (*big.Int, error) {
+	var b *big.Int
+	switch x := v.(type) {
+	case *big.Int:
+		b = x
+	case uint8, uint16, uint, uint32, uint64:
+		u, _ := ei.N(x).Uint64()
+		b = new(big.Int).SetUint64(u)
+	case int8, int16, int, int32, int64:
+		i, _ := ei.N(x).Int64()
+		b = big.NewInt(i)
+	case string:
+		var ok bool
+		if b, ok = new(big.Int).SetString(x, 0); !ok {
+			return nil, fmt.Errorf("invalid value \"%s\" for big integer field '%s'", x, field.name)
+		}
+	}
+	if b == nil {
+		return nil, fmt.Errorf("can't set big integer field '%s' to %v (%T)", field.name, v, v)
+	}
+	b, err := buffer.FitBigInt(b, field.size, field.signed, field.overflow)
+	if err != nil {
+		return nil, fmt.Errorf("field '%s': %w", field.name, err)
+	}
+	return b, nil
+}
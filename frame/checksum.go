@@ -0,0 +1,187 @@
+package frame
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strings"
+
+	"github.com/nayarsystems/buffer/buffer"
+	"github.com/nayarsystems/buffer/crc"
+)
+
+// ChecksumDesc turns an unsigned integer field into a checksum of the fields
+// From..To (both included), which default to the first field and to the
+// field placed just before the checksum. Encode fills the field and Decode
+// verifies it, returning a *ChecksumError on mismatch.
+//
+// Algorithm is one of:
+//   - a CRC preset name (see crc.Presets). The field size must match the CRC
+//     width, and defaults to it.
+//   - "xor": XOR of the range split in groups of the field size.
+//   - "sum": sum of the range bytes modulo 2^size.
+//   - "fletcher": Fletcher-16, 32 or 64 depending on the field size.
+//   - "adler": Adler-32.
+//
+// Groups are read as GetBitsToUint64 does and a trailing incomplete group is
+// padded with zeros.
+//
+// The checksum is written most significant byte first unless LittleEndian is
+// set, as protocols using reflected CRCs such as Modbus RTU do. The field
+// value is always the checksum itself.
+type ChecksumDesc struct {
+	Algorithm    string `json:"algorithm"`
+	From         string `json:"from,omitempty"`
+	To           string `json:"to,omitempty"`
+	LittleEndian bool   `json:"littleEndian,omitempty"`
+}
+
+// ChecksumError is returned by Decode when a checksum field doesn't match the
+// checksum of its range.
+type ChecksumError struct {
+	Field string
+	// Expected is the checksum computed from the data.
+	Expected uint64
+	// Actual is the value found in the checksum field.
+	Actual uint64
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch in field '%s' (expected: 0x%x  actual: 0x%x)", e.Field, e.Expected, e.Actual)
+}
+
+// init validates the descriptor, setting the default field size.
+func (d *ChecksumDesc) init(field *field) (err error) {
+	switch strings.ToLower(d.Algorithm) {
+	case "xor", "sum", "fletcher":
+		if field.size == 0 {
+			// as any other integer field, size defaults to the type size
+			field.size = int(reflect.TypeOf(field.defaultValue).Size()) * 8
+		}
+	}
+	switch strings.ToLower(d.Algorithm) {
+	case "xor", "sum":
+		if field.size <= 0 || field.size > 64 {
+			err = fmt.Errorf("invalid size value (%d) for %s checksum field '%s' (must be 1..64)", field.size, d.Algorithm, field.name)
+		}
+	case "fletcher":
+		if field.size != 16 && field.size != 32 && field.size != 64 {
+			err = fmt.Errorf("invalid size value (%d) for Fletcher checksum field '%s' (must be 16, 32 or 64)", field.size, field.name)
+		}
+	case "adler":
+		if field.size == 0 {
+			field.size = 32
+		}
+		if field.size != 32 {
+			err = fmt.Errorf("invalid size value (%d) for Adler checksum field '%s' (must be 32)", field.size, field.name)
+		}
+	default:
+		if field.crc, err = crc.NewPreset(d.Algorithm); err != nil {
+			return fmt.Errorf("invalid checksum algorithm for field '%s': %w", field.name, err)
+		}
+		width := field.crc.Params().Width
+		if field.size == 0 {
+			field.size = width
+		}
+		if field.size != width {
+			err = fmt.Errorf("invalid size value (%d) for %s field '%s' (must be %d)", field.size, d.Algorithm, field.name, width)
+		}
+	}
+	if err == nil && d.LittleEndian && field.size%8 != 0 {
+		err = fmt.Errorf("invalid size value (%d) for little endian checksum field '%s' (must be a multiple of 8)", field.size, field.name)
+	}
+	return err
+}
+
+// wireValue converts between a checksum and the bits of its field, which
+// only differ in little endian fields. The conversion is its own inverse.
+func (d *ChecksumDesc) wireValue(v uint64, size int) uint64 {
+	if !d.LittleEndian {
+		return v
+	}
+	return bits.ReverseBytes64(v) >> (64 - size)
+}
+
+// checksumRange returns the bit range covered by a checksum field.
+func (f *Frame) checksumRange(sumField *field) (idx int, numBits int, err error) {
+	from, to := sumField.checksum.From, sumField.checksum.To
+	var first, last *field
+	for _, ff := range f.fields {
+		if ff.name == from || (from == "" && ff.offset == 0) {
+			first = ff
+		}
+		if ff.name == to || (to == "" && ff.offset+ff.size == sumField.offset && ff != sumField) {
+			last = ff
+		}
+	}
+	switch {
+	case first == nil:
+		return 0, 0, fmt.Errorf("unknown first field '%s' for checksum field '%s'", from, sumField.name)
+	case last == nil:
+		return 0, 0, fmt.Errorf("unknown last field '%s' for checksum field '%s'", to, sumField.name)
+	case last.offset+last.size <= first.offset:
+		return 0, 0, fmt.Errorf("empty range for checksum field '%s'", sumField.name)
+	case sumField.offset < last.offset+last.size && sumField.offset+sumField.size > first.offset:
+		return 0, 0, fmt.Errorf("checksum field '%s' inside its own range", sumField.name)
+	}
+	return first.offset, last.offset + last.size - first.offset, nil
+}
+
+// computeChecksum returns the checksum of the range of field in buf.
+func (f *Frame) computeChecksum(buf *buffer.Buffer, field *field) (uint64, error) {
+	idx, numBits, err := f.checksumRange(field)
+	if err != nil {
+		return 0, err
+	}
+	if field.crc != nil {
+		return field.crc.Checksum(buf, idx, numBits)
+	}
+	data, err := buf.Slice(idx, numBits)
+	if err != nil {
+		return 0, err
+	}
+	mask := ^uint64(0) >> (64 - field.size)
+	var sum uint64
+	switch strings.ToLower(field.checksum.Algorithm) {
+	case "xor":
+		err = forEachGroup(data, field.size, func(v uint64) { sum ^= v })
+	case "sum":
+		err = forEachGroup(data, 8, func(v uint64) { sum += v })
+	case "fletcher":
+		half := field.size / 2
+		mod := uint64(1)<<half - 1
+		var sum1, sum2 uint64
+		err = forEachGroup(data, half, func(v uint64) {
+			sum1 = (sum1 + v) % mod
+			sum2 = (sum2 + sum1) % mod
+		})
+		sum = sum2<<half | sum1
+	case "adler":
+		a, b := uint64(1), uint64(0)
+		err = forEachGroup(data, 8, func(v uint64) {
+			a = (a + v) % 65521
+			b = (b + a) % 65521
+		})
+		sum = b<<16 | a
+	}
+	return sum & mask, err
+}
+
+// forEachGroup calls fn with every group of size bits of data.
+func forEachGroup(data *buffer.Buffer, size int, fn func(v uint64)) error {
+	for pos := 0; pos < data.GetBitSize(); pos += size {
+		n := data.GetBitSize() - pos
+		if n > size {
+			n = size
+		}
+		v, err := data.GetBitsToUint64(pos, n)
+		if err != nil {
+			return err
+		}
+		if data.GetBitOrder() == buffer.MSBFirst {
+			v <<= size - n
+		}
+		fn(v)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package frame
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// FixedPointDesc turns a field into a Qm.n fixed-point number. The field is
+// Set and Get as a float64 and encoded as the integer value*2^FracBits using
+// the field size as the total number of bits. Set stores the value rounded
+// to a multiple of 2^-FracBits (and saturated), as Encode writes it.
+type FixedPointDesc struct {
+	Signed   bool `json:"signed"`
+	FracBits int  `json:"fracBits"`
+	// Saturate clamps out of range values to the closest representable one
+	// instead of failing.
+	Saturate bool `json:"saturate"`
+}
+
+// scale returns v*2^FracBits rounded to the nearest integer, checked against
+// the range of a field of size bits.
+func (d *FixedPointDesc) scale(fieldName string, size int, v float64) (float64, error) {
+	if math.IsNaN(v) {
+		return 0, fmt.Errorf("NaN can't be stored in fixed-point field '%s'", fieldName)
+	}
+	scaled := math.Round(math.Ldexp(v, d.FracBits))
+	min, limit := 0.0, math.Ldexp(1, size)
+	if d.Signed {
+		min, limit = -math.Ldexp(1, size-1), math.Ldexp(1, size-1)
+	}
+	// limit (max+1) is a power of two, so it is exact even for 64-bit fields
+	if scaled >= min && scaled < limit {
+		return scaled, nil
+	}
+	if !d.Saturate {
+		return 0, fmt.Errorf("value %v out of range for fixed-point field '%s' (min: %v  max: %v)",
+			v, fieldName, math.Ldexp(min, -d.FracBits), math.Ldexp(limit-1, -d.FracBits))
+	}
+	if scaled < min {
+		return min, nil
+	}
+	return limit, nil
+}
+
+// quantize returns the value a field of size bits actually holds for v: v
+// rounded to the nearest multiple of 2^-FracBits, saturated if enabled.
+func (d *FixedPointDesc) quantize(fieldName string, size int, v float64) (float64, error) {
+	scaled, err := d.scale(fieldName, size, v)
+	if err != nil {
+		return 0, err
+	}
+	limit := math.Ldexp(1, size)
+	if d.Signed {
+		limit = math.Ldexp(1, size-1)
+	}
+	if scaled >= limit {
+		// not exact for 64-bit fields, encode saturates again
+		scaled = limit - 1
+	}
+	return math.Ldexp(scaled, -d.FracBits), nil
+}
+
+func (d *FixedPointDesc) encode(buf *buffer.Buffer, field *field, v float64) error {
+	scaled, err := d.scale(field.name, field.size, v)
+	if err != nil {
+		return err
+	}
+	// saturated values may lie just outside the integer range
+	if d.Signed {
+		var raw int64
+		switch limit := math.Ldexp(1, field.size-1); {
+		case scaled >= limit:
+			raw = int64(^uint64(0) >> (65 - field.size))
+		case scaled <= -limit:
+			raw = int64(-1) << (field.size - 1)
+		default:
+			raw = int64(scaled)
+		}
+		return buf.SetBitsFromInt64(field.offset, raw, field.size)
+	}
+	raw := ^uint64(0) >> (64 - field.size)
+	if scaled < math.Ldexp(1, field.size) {
+		raw = uint64(scaled)
+	}
+	return buf.SetBitsFromUint64(field.offset, raw, field.size)
+}
+
+func (d *FixedPointDesc) decode(buf *buffer.Buffer, field *field) (float64, error) {
+	if d.Signed {
+		raw, err := buf.GetBitsToInt64(field.offset, field.size)
+		return math.Ldexp(float64(raw), -d.FracBits), err
+	}
+	raw, err := buf.GetBitsToUint64(field.offset, field.size)
+	return math.Ldexp(float64(raw), -d.FracBits), err
+}
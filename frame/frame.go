@@ -4,17 +4,31 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"reflect"
 
 	"github.com/jaracil/ei"
 	"github.com/nayarsystems/buffer/buffer"
+	"github.com/nayarsystems/buffer/crc"
 	"github.com/nayarsystems/buffer/vars"
 )
 
 type FieldDesc struct {
-	Name         string      `json:"name"`
-	Size         int         `json:"size"`
-	DefaultValue interface{} `json:"defaultValue"`
+	Name         string          `json:"name"`
+	Size         int             `json:"size"`
+	DefaultValue interface{}     `json:"defaultValue"`
+	FixedPoint   *FixedPointDesc `json:"fixedPoint,omitempty"`
+	BCD          *BCDDesc        `json:"bcd,omitempty"`
+	Checksum     *ChecksumDesc   `json:"checksum,omitempty"`
+	// Overflow selects how integer fields handle values out of the range
+	// of Size bits, both on Set and on Encode.
+	Overflow buffer.OverflowMode `json:"overflow,omitempty"`
+	// Signed makes *big.Int fields hold two's complement values.
+	Signed bool `json:"signed,omitempty"`
+	// Encoding selects the representation of signed integer fields.
+	Encoding buffer.SignedEncoding `json:"encoding,omitempty"`
+	// Gray stores unsigned integer fields as their Gray code.
+	Gray bool `json:"gray,omitempty"`
 }
 
 type Frame struct {
@@ -35,9 +49,10 @@ func CreateFrame() *Frame {
 
 func (f *Frame) GetCopy() *Frame {
 	fcopy := CreateFrame()
-	for fieldName, field := range f.fieldsMap {
+	// keep the field order, checksums are computed following it
+	for _, field := range f.fields {
 		fieldCopy := *field
-		fcopy.fieldsMap[fieldName] = &fieldCopy
+		fcopy.fieldsMap[field.name] = &fieldCopy
 		fcopy.fields = append(fcopy.fields, &fieldCopy)
 	}
 	fcopy.vars = f.vars.GetCopy()
@@ -69,6 +84,30 @@ func (f *Frame) getBufferFieldValueFromString(fieldName, s string) (newBuf []byt
 }
 
 func (f *Frame) Same(fieldName string, newValue interface{}) (same bool, err error) {
+	if field, ok := f.fieldsMap[fieldName]; ok && field.fixedPoint != nil {
+		var v float64
+		if v, err = ei.N(newValue).Float64(); err != nil {
+			return false, nil
+		}
+		if v, err = field.fixedPoint.quantize(fieldName, field.size, v); err != nil {
+			return false, nil
+		}
+		return f.vars.Same(fieldName, v)
+	}
+	if field, ok := f.fieldsMap[fieldName]; ok && field.bcd != nil {
+		var v interface{}
+		if v, err = field.bcd.value(field, field.defaultValue, newValue); err != nil {
+			return false, err
+		}
+		return f.vars.Same(fieldName, v)
+	}
+	if field, ok := f.fieldsMap[fieldName]; ok && isBigIntField(field) {
+		var v *big.Int
+		if v, err = bigValue(field, newValue); err != nil {
+			return false, err
+		}
+		return f.vars.Same(fieldName, v)
+	}
 	switch v := newValue.(type) {
 	case string:
 		var newBuf []byte
@@ -84,6 +123,36 @@ func (f *Frame) Same(fieldName string, newValue interface{}) (same bool, err err
 }
 
 func (f *Frame) Set(fieldName string, newValue interface{}) (err error) {
+	if field, ok := f.fieldsMap[fieldName]; ok && field.fixedPoint != nil {
+		var v float64
+		if v, err = ei.N(newValue).Float64(); err != nil {
+			return fmt.Errorf("can't set fixed-point field '%s' to %v: %w", fieldName, newValue, err)
+		}
+		// store what Encode writes, so Get matches a decoded frame
+		if v, err = field.fixedPoint.quantize(fieldName, field.size, v); err != nil {
+			return err
+		}
+		return f.vars.Set(fieldName, v)
+	}
+	if field, ok := f.fieldsMap[fieldName]; ok && field.bcd != nil {
+		var v interface{}
+		if v, err = field.bcd.value(field, field.defaultValue, newValue); err != nil {
+			return err
+		}
+		return f.vars.Set(fieldName, v)
+	}
+	if field, ok := f.fieldsMap[fieldName]; ok && isBigIntField(field) {
+		var v *big.Int
+		if v, err = bigValue(field, newValue); err != nil {
+			return err
+		}
+		return f.vars.Set(fieldName, v)
+	}
+	if field, ok := f.fieldsMap[fieldName]; ok && isIntegerField(field) {
+		if newValue, err = fitOverflow(field, newValue); err != nil {
+			return err
+		}
+	}
 	switch v := newValue.(type) {
 	case string:
 		var newBuf []byte
@@ -109,7 +178,7 @@ func (f *Frame) GetTo(fieldName string, out interface{}) (err error) {
 func (f *Frame) GetFieldsDesc() []*FieldDesc {
 	fields := []*FieldDesc{}
 	for _, ff := range f.fields {
-		fields = append(fields, &FieldDesc{Name: ff.name, Size: ff.size, DefaultValue: ff.defaultValue})
+		fields = append(fields, &FieldDesc{Name: ff.name, Size: ff.size, DefaultValue: ff.defaultValue, FixedPoint: ff.fixedPoint, BCD: ff.bcd, Checksum: ff.checksum, Overflow: ff.overflow, Signed: ff.signed, Encoding: ff.encoding, Gray: ff.gray})
 	}
 	return fields
 }
@@ -128,23 +197,115 @@ func (f *Frame) GetByteSize() int {
 
 func (f *Frame) AddFields(newFields []*FieldDesc) error {
 	for _, desc := range newFields {
-		field := &field{name: desc.Name, size: desc.Size, defaultValue: desc.DefaultValue}
+		field := &field{name: desc.Name, size: desc.Size, defaultValue: desc.DefaultValue, fixedPoint: desc.FixedPoint, bcd: desc.BCD, checksum: desc.Checksum, overflow: desc.Overflow, signed: desc.Signed, encoding: desc.Encoding, gray: desc.Gray}
+		if field.fixedPoint != nil {
+			if field.defaultValue == nil {
+				field.defaultValue = float64(0)
+			}
+			v, err := ei.N(field.defaultValue).Float64()
+			if err != nil {
+				return fmt.Errorf("invalid default value for fixed-point field '%s': %w", field.name, err)
+			}
+			field.defaultValue = v
+		}
+		if field.bcd != nil {
+			if field.defaultValue == nil {
+				field.defaultValue = int64(0)
+			}
+			v, err := field.bcd.value(field, field.defaultValue, field.defaultValue)
+			if err != nil {
+				return fmt.Errorf("invalid default value for BCD field '%s': %w", field.name, err)
+			}
+			field.defaultValue = v
+		}
+		if v, ok := field.defaultValue.(*big.Int); ok && v != nil {
+			// the default is shared with the vars bank, keep it private
+			field.defaultValue = new(big.Int).Set(v)
+		}
+		if field.checksum != nil && field.defaultValue == nil {
+			field.defaultValue = uint64(0)
+		}
 		f.fieldsMap[desc.Name] = field
 		f.fields = append(f.fields, field)
 	}
 	for _, field := range f.fields {
 		f.vars.InitVar(field.name, field.defaultValue, nil)
 		field.offset = f.bitSize
+		if field.overflow != buffer.OverflowTruncate {
+			if field.overflow != buffer.OverflowCheck && field.overflow != buffer.OverflowSaturate {
+				return fmt.Errorf("invalid overflow mode (%d) for field '%s'", int(field.overflow), field.name)
+			}
+			if !isIntegerField(field) && !isBigIntField(field) || field.fixedPoint != nil || field.bcd != nil {
+				return fmt.Errorf("overflow mode set for non integer field '%s'", field.name)
+			}
+		}
+		if field.encoding != buffer.TwosComplement {
+			if field.encoding < buffer.TwosComplement || field.encoding > buffer.ZigZag {
+				return fmt.Errorf("invalid signed encoding (%d) for field '%s'", int(field.encoding), field.name)
+			}
+			if !isSignedField(field) || field.fixedPoint != nil || field.bcd != nil {
+				return fmt.Errorf("signed encoding set for non signed integer field '%s'", field.name)
+			}
+		}
+		if field.gray && (!isIntegerField(field) || isSignedField(field) || field.fixedPoint != nil || field.bcd != nil || field.checksum != nil) {
+			return fmt.Errorf("gray code set for non unsigned integer field '%s'", field.name)
+		}
+		if field.signed && (!isBigIntField(field) || field.fixedPoint != nil || field.bcd != nil) {
+			return fmt.Errorf("signed set for non big integer field '%s'", field.name)
+		}
+		if field.checksum != nil {
+			switch field.defaultValue.(type) {
+			case uint8, uint16, uint, uint32, uint64:
+			default:
+				return fmt.Errorf("checksum field '%s' must be an unsigned integer", field.name)
+			}
+			if field.fixedPoint != nil || field.bcd != nil {
+				return fmt.Errorf("checksum field '%s' can't be fixed-point or BCD", field.name)
+			}
+			if err := field.checksum.init(field); err != nil {
+				return err
+			}
+		}
+		if field.fixedPoint != nil {
+			if field.size <= 0 || field.size > 64 {
+				return fmt.Errorf("invalid size value (%d) for fixed-point field '%s' (must be 1..64)", field.size, field.name)
+			}
+			if field.fixedPoint.FracBits < 0 {
+				return fmt.Errorf("invalid fractional bits (%d) for fixed-point field '%s'", field.fixedPoint.FracBits, field.name)
+			}
+			v, err := field.fixedPoint.quantize(field.name, field.size, field.defaultValue.(float64))
+			if err != nil {
+				return fmt.Errorf("invalid default value: %w", err)
+			}
+			field.defaultValue = v
+			f.vars.InitVar(field.name, field.defaultValue, nil)
+			f.bitSize += field.size
+			continue
+		}
+		if field.bcd != nil {
+			f.bitSize += field.size
+			continue
+		}
+		if isBigIntField(field) {
+			// there's no size to infer from a *big.Int
+			if field.size <= 0 {
+				return fmt.Errorf("invalid size value (%d) for big integer field '%s' (must be > 0)", field.size, field.name)
+			}
+			if _, err := bigValue(field, field.defaultValue); err != nil {
+				return fmt.Errorf("invalid default value: %w", err)
+			}
+			f.bitSize += field.size
+			continue
+		}
 		switch field.defaultValue.(type) {
 		case bool:
 			// force size
 			field.size = 1
-		case float32:
-			// force size
-			field.size = 32
-		case float64:
-			// force size
-			field.size = 64
+		case float32, float64:
+			// force size, except for half floats
+			if field.size != 16 {
+				field.size = int(reflect.TypeOf(field.defaultValue).Size()) * 8
+			}
 		default:
 			if field.size <= 0 {
 				// Size not specified
@@ -175,8 +336,18 @@ func (f *Frame) AddFields(newFields []*FieldDesc) error {
 				}
 			}
 		}
+		if _, err := fitOverflow(field, field.defaultValue); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
 		f.bitSize += field.size
 	}
+	for _, field := range f.fields {
+		if field.checksum != nil {
+			if _, _, err := f.checksumRange(field); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -184,6 +355,24 @@ func (f *Frame) encode(buffer *buffer.Buffer) error {
 	for _, field := range f.fields {
 		currentValue, _ := f.vars.Get(field.name)
 		var err error
+		if field.fixedPoint != nil {
+			if err = field.fixedPoint.encode(buffer, field, currentValue.(float64)); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.bcd != nil {
+			if err = field.bcd.encode(buffer, field, currentValue); err != nil {
+				return err
+			}
+			continue
+		}
+		if isBigIntField(field) {
+			if err = buffer.SetBitsFromBigIntWithOverflow(field.offset, currentValue.(*big.Int), field.size, field.signed, field.overflow); err != nil {
+				return fmt.Errorf("field '%s': %w", field.name, err)
+			}
+			continue
+		}
 		switch actualValue := currentValue.(type) {
 		case bool:
 			var v bool
@@ -193,12 +382,31 @@ func (f *Frame) encode(buffer *buffer.Buffer) error {
 		case uint8, uint16, uint, uint32, uint64:
 			var v uint64
 			if v, err = ei.N(currentValue).Uint64(); err == nil {
-				err = buffer.SetBitsFromUint64(field.offset, v, field.size)
+				if field.gray {
+					if v, err = fitUint64(field, v); err == nil {
+						err = buffer.SetBitsFromUint64Gray(field.offset, v, field.size)
+					}
+				} else {
+					err = buffer.SetBitsFromUint64WithOverflow(field.offset, v, field.size, field.overflow)
+				}
+				if err != nil {
+					err = fmt.Errorf("field '%s': %w", field.name, err)
+				}
 			}
 		case int8, int16, int, int32, int64:
 			var v int64
 			if v, err = ei.N(currentValue).Int64(); err == nil {
-				err = buffer.SetBitsFromInt64(field.offset, v, field.size)
+				if v, err = fitInt64(field, v); err == nil {
+					err = buffer.SetBitsFromInt64WithEncoding(field.offset, v, field.size, field.encoding)
+				}
+				if err != nil {
+					err = fmt.Errorf("field '%s': %w", field.name, err)
+				}
+			}
+		case float32, float64:
+			var v float64
+			if v, err = ei.N(currentValue).Float64(); err == nil {
+				err = encodeFloat(buffer, field, v)
 			}
 		case []byte:
 			minArraySize := field.size / 8
@@ -223,6 +431,19 @@ func (f *Frame) encode(buffer *buffer.Buffer) error {
 			return err
 		}
 	}
+	// checksums are computed in field order, so they may cover previous ones
+	for _, field := range f.fields {
+		if field.checksum == nil {
+			continue
+		}
+		v, err := f.computeChecksum(buffer, field)
+		if err != nil {
+			return err
+		}
+		if err = buffer.SetBitsFromUint64(field.offset, field.checksum.wireValue(v, field.size), field.size); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -252,9 +473,63 @@ func (f *Frame) Decode(rawInput []byte) error {
 	if err != nil {
 		return err
 	}
+	// verify the checksums first, so a corrupted frame doesn't modify the
+	// current values
+	for _, field := range f.fields {
+		if field.checksum == nil {
+			continue
+		}
+		expected, err := f.computeChecksum(input, field)
+		if err != nil {
+			return err
+		}
+		actual, err := input.GetBitsToUint64(field.offset, field.size)
+		if err != nil {
+			return err
+		}
+		actual = field.checksum.wireValue(actual, field.size)
+		if expected != actual {
+			return &ChecksumError{Field: field.name, Expected: expected, Actual: actual}
+		}
+	}
 	for _, field := range f.fields {
 		currentValue, _ := f.vars.Get(field.name)
 		var newValue interface{}
+		if field.fixedPoint != nil {
+			v, err := field.fixedPoint.decode(input, field)
+			if err != nil {
+				return err
+			}
+			if err := f.vars.Set(field.name, v); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.bcd != nil {
+			v, err := field.bcd.decode(input, field, currentValue)
+			if err != nil {
+				return err
+			}
+			if err := f.vars.Set(field.name, v); err != nil {
+				return err
+			}
+			continue
+		}
+		if isBigIntField(field) {
+			var v *big.Int
+			if field.signed {
+				v, err = input.GetBitsToBigInt(field.offset, field.size)
+			} else {
+				v, err = input.GetBitsToBigUint(field.offset, field.size)
+			}
+			if err != nil {
+				return err
+			}
+			if err := f.vars.Set(field.name, v); err != nil {
+				return err
+			}
+			continue
+		}
 		switch currentValue.(type) {
 		case bool:
 			var err error
@@ -262,10 +537,19 @@ func (f *Frame) Decode(rawInput []byte) error {
 				return err
 			}
 		case uint8, uint16, uint, uint32, uint64:
-			newRawValue, err := input.GetBitsToUint64(field.offset, field.size)
+			var newRawValue uint64
+			var err error
+			if field.gray {
+				newRawValue, err = input.GetBitsToUint64Gray(field.offset, field.size)
+			} else {
+				newRawValue, err = input.GetBitsToUint64(field.offset, field.size)
+			}
 			if err != nil {
 				return err
 			}
+			if field.checksum != nil {
+				newRawValue = field.checksum.wireValue(newRawValue, field.size)
+			}
 			switch currentValue.(type) {
 			case uint8:
 				newValue = uint8(newRawValue)
@@ -281,7 +565,7 @@ func (f *Frame) Decode(rawInput []byte) error {
 				return fmt.Errorf("unknown type of field '%s'", field.name)
 			}
 		case int8, int16, int, int32, int64:
-			newRawValue, err := input.GetBitsToInt64(field.offset, field.size)
+			newRawValue, err := input.GetBitsToInt64WithEncoding(field.offset, field.size, field.encoding)
 			if err != nil {
 				return err
 			}
@@ -299,26 +583,22 @@ func (f *Frame) Decode(rawInput []byte) error {
 			default:
 				return fmt.Errorf("unknown type of field '%s'", field.name)
 			}
+		case float32, float64:
+			floatValue, err := decodeFloat(input, field)
+			if err != nil {
+				return err
+			}
+			if _, ok := currentValue.(float32); ok {
+				newValue = float32(floatValue)
+			} else {
+				newValue = floatValue
+			}
 		default:
 			data, err := input.GetBitsToRawBuffer(field.offset, field.size)
 			if err != nil {
 				return err
 			}
 			switch currentValue.(type) {
-			case float32:
-				breader := bytes.NewReader(data)
-				var floatValue float32
-				if err := binary.Read(breader, binary.BigEndian, &floatValue); err != nil {
-					return err
-				}
-				newValue = floatValue
-			case float64:
-				var floatValue float64
-				breader := bytes.NewReader(data)
-				if err := binary.Read(breader, binary.BigEndian, &floatValue); err != nil {
-					return err
-				}
-				newValue = floatValue
 			case []byte:
 				newValue = data
 			}
@@ -330,9 +610,41 @@ func (f *Frame) Decode(rawInput []byte) error {
 	return nil
 }
 
+// encodeFloat stores v as an IEEE 754 value of the field size.
+func encodeFloat(buf *buffer.Buffer, field *field, v float64) error {
+	switch field.size {
+	case 16:
+		return buf.SetFloat16(field.offset, v)
+	case 32:
+		return buf.SetFloat32(field.offset, float32(v))
+	default:
+		return buf.SetFloat64(field.offset, v)
+	}
+}
+
+func decodeFloat(buf *buffer.Buffer, field *field) (float64, error) {
+	switch field.size {
+	case 16:
+		return buf.GetFloat16(field.offset)
+	case 32:
+		v, err := buf.GetFloat32(field.offset)
+		return float64(v), err
+	default:
+		return buf.GetFloat64(field.offset)
+	}
+}
+
 type field struct {
 	name         string
 	size         int
 	offset       int
 	defaultValue interface{}
+	fixedPoint   *FixedPointDesc
+	bcd          *BCDDesc
+	overflow     buffer.OverflowMode
+	signed       bool
+	encoding     buffer.SignedEncoding
+	gray         bool
+	checksum     *ChecksumDesc
+	crc          *crc.CRC
 }
@@ -1,8 +1,12 @@
 package frame
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/nayarsystems/buffer/buffer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -205,6 +209,370 @@ func Test_SameValue(t *testing.T) {
 	require.False(t, same)
 }
 
+func Test_HalfFloatFields(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "HALF32", Size: 16, DefaultValue: float32(0)},
+		{Name: "HALF64", Size: 16, DefaultValue: float64(0)},
+		// sizes other than 16 are ignored, as in frames defined before half floats
+		{Name: "DOUBLE64", Size: 32, DefaultValue: float64(0)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 96, frame.GetBitSize())
+
+	require.NoError(t, frame.Set("HALF32", 1.5))
+	require.NoError(t, frame.Set("HALF64", -65504))
+	require.NoError(t, frame.Set("DOUBLE64", 0.1))
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x3e, 0x00, 0xfb, 0xff}, data[:4])
+
+	require.NoError(t, frame.Set("HALF32", 0.1))
+	require.NoError(t, frame.Decode(data))
+	v, err := frame.Get("HALF32")
+	require.NoError(t, err)
+	require.Equal(t, float32(1.5), v)
+	v, err = frame.Get("HALF64")
+	require.NoError(t, err)
+	require.Equal(t, float64(-65504), v)
+	v, err = frame.Get("DOUBLE64")
+	require.NoError(t, err)
+	require.Equal(t, float64(0.1), v)
+
+	other := CreateFrame()
+	err = other.AddFields([]*FieldDesc{
+		{Name: "F32", Size: 8, DefaultValue: float32(0)},
+		{Name: "F64", Size: 24, DefaultValue: float64(0)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 96, other.GetBitSize())
+}
+
+func Test_FixedPointFields(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "Q15", Size: 16, FixedPoint: &FixedPointDesc{Signed: true, FracBits: 15}},
+		{Name: "Q15_SAT", Size: 16, FixedPoint: &FixedPointDesc{Signed: true, FracBits: 15, Saturate: true}},
+		{Name: "UQ4_4", Size: 8, DefaultValue: 1.5, FixedPoint: &FixedPointDesc{FracBits: 4}},
+		{Name: "Q31", Size: 32, FixedPoint: &FixedPointDesc{Signed: true, FracBits: 31}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 72, frame.GetBitSize())
+
+	v, err := frame.Get("UQ4_4")
+	require.NoError(t, err)
+	require.Equal(t, 1.5, v)
+
+	require.NoError(t, frame.Set("Q15", 0.5))
+	err = frame.Set("Q15", 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Q15")
+	require.NoError(t, frame.Set("Q15_SAT", 1))
+	require.NoError(t, frame.Set("Q31", -0.25))
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x40, 0x00, 0x7f, 0xff, 0x18, 0xe0, 0x00, 0x00, 0x00}, data)
+
+	require.NoError(t, frame.Set("Q15_SAT", -3))
+	require.NoError(t, frame.Set("UQ4_4", 3.1))
+	data, err = frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x80, 0x00}, data[2:4])
+	require.Equal(t, byte(0x32), data[4])
+
+	dst := CreateFrame()
+	require.NoError(t, dst.AddFields(frame.GetFieldsDesc()))
+	require.NoError(t, dst.Decode(data))
+	for name, exp := range map[string]float64{"Q15": 0.5, "Q15_SAT": -1, "UQ4_4": 3.125, "Q31": -0.25} {
+		v, err := dst.Get(name)
+		require.NoError(t, err)
+		require.Equal(t, exp, v, name)
+	}
+}
+
+func Test_FixedPointSetStoresEncodedValue(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "Q", Size: 16, DefaultValue: 0.1, FixedPoint: &FixedPointDesc{Signed: true, FracBits: 15, Saturate: true}},
+		{Name: "UQ", Size: 8, FixedPoint: &FixedPointDesc{FracBits: 4, Saturate: true}},
+	})
+	require.NoError(t, err)
+	v, err := frame.Get("Q")
+	require.NoError(t, err)
+	require.Equal(t, 3277.0/32768, v)
+
+	// saturated values read back as the encoded ones
+	require.NoError(t, frame.Set("Q", 5.0))
+	require.NoError(t, frame.Set("UQ", -2))
+	v, err = frame.Get("Q")
+	require.NoError(t, err)
+	require.Equal(t, 32767.0/32768, v)
+	v, err = frame.Get("UQ")
+	require.NoError(t, err)
+	require.Equal(t, 0.0, v)
+
+	// and rounded ones survive an encode/decode round trip
+	require.NoError(t, frame.Set("Q", 0.1))
+	require.NoError(t, frame.Set("UQ", 3.1))
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	dst := frame.GetCopy()
+	require.NoError(t, dst.Decode(data))
+	for _, name := range []string{"Q", "UQ"} {
+		exp, _ := frame.Get(name)
+		v, err := dst.Get(name)
+		require.NoError(t, err)
+		require.Equal(t, exp, v, name)
+	}
+	same, err := frame.Same("Q", 0.1)
+	require.NoError(t, err)
+	require.True(t, same)
+}
+
+func Test_FixedPoint64BitSaturation(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "S64", Size: 64, FixedPoint: &FixedPointDesc{Signed: true, Saturate: true}},
+		{Name: "U64", Size: 64, FixedPoint: &FixedPointDesc{Saturate: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, frame.Set("S64", 1e30))
+	require.NoError(t, frame.Set("U64", 1e30))
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, data[:8])
+	require.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, data[8:])
+	require.NoError(t, frame.Set("S64", -1e30))
+	require.NoError(t, frame.Set("U64", -1))
+	data, err = frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, data)
+
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", FixedPoint: &FixedPointDesc{}}})
+	require.Error(t, err)
+}
+
+func Test_BCDFields(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "SEC", Size: 8, DefaultValue: uint8(0), BCD: &BCDDesc{}},
+		{Name: "MIN", Size: 8, DefaultValue: uint8(0), BCD: &BCDDesc{}},
+		{Name: "HOUR", Size: 8, DefaultValue: uint8(0), BCD: &BCDDesc{}},
+		{Name: "FLAGS", Size: 4, DefaultValue: uint8(0)},
+		{Name: "METER", Size: 32, DefaultValue: "", BCD: &BCDDesc{}},
+		{Name: "BALANCE", Size: 24, DefaultValue: int32(0), BCD: &BCDDesc{Format: buffer.BCDPackedDecimal}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 84, frame.GetBitSize())
+
+	v, err := frame.Get("METER")
+	require.NoError(t, err)
+	require.Equal(t, "00000000", v)
+
+	require.NoError(t, frame.Set("SEC", 59))
+	require.NoError(t, frame.Set("MIN", "07"))
+	require.NoError(t, frame.Set("HOUR", 23))
+	require.NoError(t, frame.Set("FLAGS", 0xa))
+	require.NoError(t, frame.Set("METER", 1234567))
+	require.NoError(t, frame.Set("BALANCE", "-1234"))
+	v, err = frame.Get("MIN")
+	require.NoError(t, err)
+	require.Equal(t, uint8(7), v)
+	same, err := frame.Same("METER", "1234567")
+	require.NoError(t, err)
+	require.True(t, same)
+
+	err = frame.Set("SEC", 100)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "SEC")
+	require.Error(t, frame.Set("MIN", "1a"))
+	require.Error(t, frame.Set("METER", -1))
+	require.Error(t, frame.Set("BALANCE", 1.5))
+
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x59, 0x07, 0x23, 0xa0, 0x12, 0x34, 0x56, 0x70, 0x12, 0x34, 0xd0}, data)
+
+	dst := CreateFrame()
+	require.NoError(t, dst.AddFields(frame.GetFieldsDesc()))
+	require.NoError(t, dst.Decode(data))
+	for name, exp := range map[string]interface{}{"SEC": uint8(59), "MIN": uint8(7), "HOUR": uint8(23), "METER": "01234567", "BALANCE": int32(-1234)} {
+		v, err := dst.Get(name)
+		require.NoError(t, err)
+		require.Equal(t, exp, v, name)
+	}
+
+	data[1] = 0x5a
+	err = dst.Decode(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MIN")
+
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", Size: 12, BCD: &BCDDesc{Format: buffer.BCDUnpacked}}})
+	require.Error(t, err)
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", Size: 8, DefaultValue: 123, BCD: &BCDDesc{}}})
+	require.Error(t, err)
+}
+
+func Test_OverflowModes(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "WRAP", Size: 4, DefaultValue: int8(0)},
+		{Name: "CHECK", Size: 4, DefaultValue: int8(0), Overflow: buffer.OverflowCheck},
+		{Name: "SAT", Size: 4, DefaultValue: int8(0), Overflow: buffer.OverflowSaturate},
+		{Name: "USAT", Size: 4, DefaultValue: uint16(0), Overflow: buffer.OverflowSaturate},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, frame.Set("WRAP", 300))
+	err = frame.Set("CHECK", 300)
+	require.EqualError(t, err, "field 'CHECK': value 300 doesn't fit in 4 bits (min: -8  max: 7)")
+	var oe *buffer.OverflowError
+	require.True(t, errors.As(err, &oe))
+	require.NoError(t, frame.Set("CHECK", -8))
+	require.Error(t, frame.Set("CHECK", 7.5e3))
+	require.Error(t, frame.Set("CHECK", uint64(1<<63)))
+	require.NoError(t, frame.Set("SAT", 300))
+	require.NoError(t, frame.Set("USAT", -5))
+
+	v, err := frame.Get("SAT")
+	require.NoError(t, err)
+	require.Equal(t, int8(7), v)
+	v, err = frame.Get("USAT")
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), v)
+	v, err = frame.Get("CHECK")
+	require.NoError(t, err)
+	require.Equal(t, int8(-8), v)
+
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xc8, 0x70}, data)
+
+	require.NoError(t, frame.Set("USAT", 1e30))
+	v, err = frame.Get("USAT")
+	require.NoError(t, err)
+	require.Equal(t, uint16(15), v)
+
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", Size: 4, DefaultValue: 20, Overflow: buffer.OverflowCheck}})
+	require.Error(t, err)
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", Size: 8, DefaultValue: []byte{0}, Overflow: buffer.OverflowCheck}})
+	require.Error(t, err)
+	err = CreateFrame().AddFields([]*FieldDesc{{Name: "BAD", Size: 8, FixedPoint: &FixedPointDesc{}, Overflow: buffer.OverflowSaturate}})
+	require.Error(t, err)
+}
+
+func Test_OverflowCheckedOnEncode(t *testing.T) {
+	frame := CreateFrame()
+	require.NoError(t, frame.AddFields([]*FieldDesc{
+		{Name: "A", Size: 3, DefaultValue: uint8(0), Overflow: buffer.OverflowCheck},
+	}))
+	// values can still reach the vars bank without going through Set
+	require.NoError(t, frame.vars.Set("A", uint8(9)))
+	_, err := frame.Encode()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'A'")
+}
+
+func Test_ChecksumFields(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "ADDR", DefaultValue: uint8(1)},
+		{Name: "FUNC", DefaultValue: uint8(3)},
+		{Name: "REG", DefaultValue: uint16(0)},
+		{Name: "COUNT", DefaultValue: uint16(10)},
+		{Name: "CRC", DefaultValue: uint16(0), Checksum: &ChecksumDesc{Algorithm: "CRC-16/MODBUS", LittleEndian: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 64, frame.GetBitSize())
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	// Modbus RTU sends the CRC low byte first
+	require.Equal(t, []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a, 0xc5, 0xcd}, data)
+
+	dst := frame.GetCopy()
+	require.NoError(t, dst.Decode(data))
+	v, err := dst.Get("CRC")
+	require.NoError(t, err)
+	require.Equal(t, uint16(0xcdc5), v)
+
+	data[5] = 0x0b
+	err = dst.Decode(data)
+	var ce *ChecksumError
+	require.True(t, errors.As(err, &ce))
+	require.Equal(t, "CRC", ce.Field)
+	require.Equal(t, uint64(0xcdc5), ce.Actual)
+	require.NotEqual(t, ce.Actual, ce.Expected)
+	// a mismatch leaves the current values untouched
+	v, err = dst.Get("COUNT")
+	require.NoError(t, err)
+	require.Equal(t, uint16(10), v)
+}
+
+func Test_ChecksumAlgorithms(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "A", Size: 4, DefaultValue: uint8(0xa)},
+		{Name: "B", Size: 8, DefaultValue: uint8(0x5c)},
+		{Name: "C", Size: 4, DefaultValue: uint8(0x3)},
+		{Name: "XOR", Size: 8, DefaultValue: uint8(0), Checksum: &ChecksumDesc{Algorithm: "xor"}},
+		{Name: "SUM", Size: 12, DefaultValue: uint16(0), Checksum: &ChecksumDesc{Algorithm: "sum", From: "A", To: "C"}},
+		{Name: "PAD", Size: 4, DefaultValue: uint8(0)},
+		{Name: "TEXT", DefaultValue: []byte("abcde")},
+		{Name: "FLETCHER", Size: 16, DefaultValue: uint16(0), Checksum: &ChecksumDesc{Algorithm: "fletcher", From: "TEXT"}},
+		{Name: "WIKI", DefaultValue: []byte("Wikipedia")},
+		{Name: "ADLER", DefaultValue: uint32(0), Checksum: &ChecksumDesc{Algorithm: "adler", From: "WIKI"}},
+	})
+	require.NoError(t, err)
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xa5, 0xc3, 0x66, 0x16, 0x80}, data[:5])
+	require.Equal(t, []byte{0xc8, 0xf0}, data[10:12])
+	require.Equal(t, []byte{0x11, 0xe6, 0x03, 0x98}, data[21:])
+
+	dst := frame.GetCopy()
+	require.NoError(t, dst.Decode(data))
+	data[0] ^= 0x10
+	var ce *ChecksumError
+	require.True(t, errors.As(dst.Decode(data), &ce))
+	require.Equal(t, "XOR", ce.Field)
+	require.Equal(t, uint64(0x76), ce.Expected)
+	require.Equal(t, uint64(0x66), ce.Actual)
+}
+
+func Test_ChecksumDefaultSizes(t *testing.T) {
+	frame := CreateFrame()
+	err := frame.AddFields([]*FieldDesc{
+		{Name: "DATA", DefaultValue: []byte("abcde")},
+		{Name: "XOR", DefaultValue: uint8(0), Checksum: &ChecksumDesc{Algorithm: "xor"}},
+		{Name: "SUM", DefaultValue: uint16(0), Checksum: &ChecksumDesc{Algorithm: "sum"}},
+		{Name: "FLETCHER", DefaultValue: uint16(0), Checksum: &ChecksumDesc{Algorithm: "fletcher", From: "DATA"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 80, frame.GetBitSize())
+	desc := frame.GetFieldsDesc()
+	require.Equal(t, []int{8, 16, 16}, []int{desc[1].Size, desc[2].Size, desc[3].Size})
+
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x61, 0x02, 0x50}, data[5:8])
+	require.NoError(t, frame.GetCopy().Decode(data))
+}
+
+func Test_ChecksumFieldErrors(t *testing.T) {
+	for _, fields := range [][]*FieldDesc{
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Checksum: &ChecksumDesc{Algorithm: "md5"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 8, Checksum: &ChecksumDesc{Algorithm: "CRC-32"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 12, Checksum: &ChecksumDesc{Algorithm: "fletcher"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 8, DefaultValue: int8(0), Checksum: &ChecksumDesc{Algorithm: "xor"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 8, Checksum: &ChecksumDesc{Algorithm: "xor", To: "S"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 8, Checksum: &ChecksumDesc{Algorithm: "xor", From: "X"}}},
+		{{Name: "S", Size: 8, Checksum: &ChecksumDesc{Algorithm: "xor"}}},
+		{{Name: "A", DefaultValue: uint8(0)}, {Name: "S", Size: 12, Checksum: &ChecksumDesc{Algorithm: "xor", LittleEndian: true}}},
+	} {
+		require.Error(t, CreateFrame().AddFields(fields))
+	}
+}
+
 func getBufferFieldInfoCopy(fields []*FieldDesc) []*FieldDesc {
 	fieldsCopy := []*FieldDesc{}
 	for _, field := range fields {
@@ -213,3 +581,171 @@ func getBufferFieldInfoCopy(fields []*FieldDesc) []*FieldDesc {
 	}
 	return fieldsCopy
 }
+
+func Test_BigIntFields(t *testing.T) {
+	fields := []*FieldDesc{
+		{Name: "SERIAL", Size: 96, DefaultValue: new(big.Int)},
+		{Name: "ID", Size: 128, DefaultValue: big.NewInt(-1), Signed: true},
+		{Name: "FLAGS", Size: 8, DefaultValue: uint8(0)},
+	}
+	frame := CreateFrame()
+	require.NoError(t, frame.AddFields(fields))
+	require.Equal(t, 232, frame.GetBitSize())
+
+	serial, _ := new(big.Int).SetString("0x0123456789abcdef01234567", 0)
+	require.NoError(t, frame.Set("SERIAL", serial))
+	require.NoError(t, frame.Set("ID", -2))
+	require.NoError(t, frame.Set("FLAGS", 0x5a))
+	// the stored value is a copy
+	serial.SetInt64(0)
+
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	expected := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67}
+	for i := 0; i < 15; i++ {
+		expected = append(expected, 0xff)
+	}
+	expected = append(expected, 0xfe, 0x5a)
+	require.Equal(t, expected, data)
+
+	decoded := CreateFrame()
+	require.NoError(t, decoded.AddFields(fields))
+	require.NoError(t, decoded.Decode(data))
+	v, err := decoded.Get("SERIAL")
+	require.NoError(t, err)
+	require.Equal(t, "0x123456789abcdef01234567", fmt.Sprintf("%#x", v))
+	v, err = decoded.Get("ID")
+	require.NoError(t, err)
+	require.Equal(t, "-2", v.(*big.Int).String())
+	out := new(big.Int)
+	require.NoError(t, decoded.GetTo("SERIAL", out))
+	require.Equal(t, "0x123456789abcdef01234567", fmt.Sprintf("%#x", out))
+
+	same, err := decoded.Same("SERIAL", "0x0123456789abcdef01234567")
+	require.NoError(t, err)
+	require.True(t, same)
+	same, err = decoded.Same("ID", big.NewInt(-2))
+	require.NoError(t, err)
+	require.True(t, same)
+	require.Error(t, decoded.Set("SERIAL", "serial"))
+	require.Error(t, decoded.Set("SERIAL", 1.5))
+
+	// unsigned fields decode the same bits as positive values
+	unsigned := CreateFrame()
+	require.NoError(t, unsigned.AddFields([]*FieldDesc{{Name: "ID", Size: 128, DefaultValue: new(big.Int)}}))
+	require.NoError(t, unsigned.Decode(data[12:28]))
+	v, err = unsigned.Get("ID")
+	require.NoError(t, err)
+	require.Equal(t, "0xfffffffffffffffffffffffffffffffe", fmt.Sprintf("%#x", v))
+}
+
+func Test_BigIntFieldErrors(t *testing.T) {
+	frame := CreateFrame()
+	require.NoError(t, frame.AddFields([]*FieldDesc{
+		{Name: "CHECK", Size: 96, DefaultValue: new(big.Int), Overflow: buffer.OverflowCheck},
+		{Name: "SAT", Size: 96, DefaultValue: new(big.Int), Signed: true, Overflow: buffer.OverflowSaturate},
+	}))
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 96)
+	err := frame.Set("CHECK", tooBig)
+	require.Error(t, err)
+	var oe *buffer.OverflowError
+	require.True(t, errors.As(err, &oe))
+	require.Error(t, frame.Set("CHECK", -1))
+	require.NoError(t, frame.Set("SAT", tooBig))
+	v, err := frame.Get("SAT")
+	require.NoError(t, err)
+	require.Equal(t, "0x7fffffffffffffffffffffff", fmt.Sprintf("%#x", v))
+
+	for _, desc := range []*FieldDesc{
+		{Name: "NO_SIZE", DefaultValue: new(big.Int)},
+		{Name: "NIL", Size: 8, DefaultValue: (*big.Int)(nil)},
+		{Name: "SIGNED_INT", Size: 8, DefaultValue: int8(0), Signed: true},
+		{Name: "BAD_DEFAULT", Size: 8, DefaultValue: big.NewInt(256), Overflow: buffer.OverflowCheck},
+	} {
+		require.Error(t, CreateFrame().AddFields([]*FieldDesc{desc}), desc.Name)
+	}
+}
+
+func Test_SignedEncodingFields(t *testing.T) {
+	fields := []*FieldDesc{
+		{Name: "SM", Size: 5, DefaultValue: int8(0), Encoding: buffer.SignMagnitude, Overflow: buffer.OverflowSaturate},
+		{Name: "OC", Size: 11, DefaultValue: int16(0), Encoding: buffer.OnesComplement},
+		{Name: "OB", Size: 13, DefaultValue: int16(0), Encoding: buffer.OffsetBinary, Overflow: buffer.OverflowCheck},
+	}
+	frame := CreateFrame()
+	require.NoError(t, frame.AddFields(fields))
+	require.Equal(t, buffer.OffsetBinary, frame.GetFieldsDesc()[2].Encoding)
+
+	require.NoError(t, frame.Set("SM", -16))
+	require.NoError(t, frame.Set("OC", -1000))
+	require.NoError(t, frame.Set("OB", -4096))
+	require.Error(t, frame.Set("OB", 4096))
+	v, err := frame.Get("SM")
+	require.NoError(t, err)
+	require.Equal(t, int8(-15), v)
+
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xfc, 0x17, 0x00, 0x00}, data)
+
+	decoded := CreateFrame()
+	require.NoError(t, decoded.AddFields(fields))
+	require.NoError(t, decoded.Decode(data))
+	for name, expected := range map[string]interface{}{"SM": int8(-15), "OC": int16(-1000), "OB": int16(-4096)} {
+		v, err := decoded.Get(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, v, name)
+	}
+
+	for _, desc := range []*FieldDesc{
+		{Name: "UNSIGNED", Size: 8, DefaultValue: uint8(0), Encoding: buffer.SignMagnitude},
+		{Name: "FIXED", Size: 8, FixedPoint: &FixedPointDesc{Signed: true}, Encoding: buffer.OffsetBinary},
+		{Name: "UNKNOWN", Size: 8, DefaultValue: int8(0), Encoding: buffer.SignedEncoding(9)},
+		{Name: "BAD_DEFAULT", Size: 5, DefaultValue: int8(-16), Encoding: buffer.OnesComplement, Overflow: buffer.OverflowCheck},
+	} {
+		require.Error(t, CreateFrame().AddFields([]*FieldDesc{desc}), desc.Name)
+	}
+}
+
+func Test_GrayAndZigZagFields(t *testing.T) {
+	fields := []*FieldDesc{
+		{Name: "POS", Size: 10, DefaultValue: uint16(0), Gray: true, Overflow: buffer.OverflowSaturate},
+		{Name: "DELTA", Size: 6, DefaultValue: int8(0), Encoding: buffer.ZigZag},
+	}
+	frame := CreateFrame()
+	require.NoError(t, frame.AddFields(fields))
+	require.True(t, frame.GetFieldsDesc()[0].Gray)
+
+	require.NoError(t, frame.Set("POS", 300))
+	require.NoError(t, frame.Set("DELTA", -3))
+	data, err := frame.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x6e, 0x85}, data)
+
+	decoded := CreateFrame()
+	require.NoError(t, decoded.AddFields(fields))
+	require.NoError(t, decoded.Decode(data))
+	v, err := decoded.Get("POS")
+	require.NoError(t, err)
+	require.Equal(t, uint16(300), v)
+	v, err = decoded.Get("DELTA")
+	require.NoError(t, err)
+	require.Equal(t, int8(-3), v)
+
+	// the position saturates before being Gray coded
+	require.NoError(t, frame.Set("POS", 5000))
+	data, err = frame.Encode()
+	require.NoError(t, err)
+	require.NoError(t, decoded.Decode(data))
+	v, err = decoded.Get("POS")
+	require.NoError(t, err)
+	require.Equal(t, uint16(1023), v)
+
+	for _, desc := range []*FieldDesc{
+		{Name: "SIGNED", Size: 8, DefaultValue: int8(0), Gray: true},
+		{Name: "FLOAT", Size: 32, DefaultValue: float32(0), Gray: true},
+		{Name: "UNSIGNED", Size: 8, DefaultValue: uint8(0), Encoding: buffer.ZigZag},
+	} {
+		require.Error(t, CreateFrame().AddFields([]*FieldDesc{desc}), desc.Name)
+	}
+}
@@ -0,0 +1,89 @@
+package frame
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jaracil/ei"
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// isIntegerField reports whether the field holds an integer.
+func isIntegerField(field *field) bool {
+	switch field.defaultValue.(type) {
+	case uint8, uint16, uint, uint32, uint64, int8, int16, int, int32, int64:
+		return true
+	}
+	return false
+}
+
+// isSignedField reports whether the field holds a signed integer.
+func isSignedField(field *field) bool {
+	switch field.defaultValue.(type) {
+	case int8, int16, int, int32, int64:
+		return true
+	}
+	return false
+}
+
+// fitUint64 applies the overflow mode of an unsigned integer field to v.
+func fitUint64(field *field, v uint64) (uint64, error) {
+	return buffer.FitUint64(v, field.size, field.overflow)
+}
+
+// fitInt64 applies the overflow mode of a signed integer field to v, using
+// the range of its encoding.
+func fitInt64(field *field, v int64) (int64, error) {
+	return buffer.FitInt64WithEncoding(v, field.size, field.encoding, field.overflow)
+}
+
+// fitOverflow checks v against the range of an integer field, returning the
+// value to store. Values which are not numbers are returned unchanged.
+func fitOverflow(field *field, v interface{}) (interface{}, error) {
+	if field.overflow == buffer.OverflowTruncate {
+		return v, nil
+	}
+	var negative bool
+	var i int64
+	var u uint64
+	switch x := v.(type) {
+	case int8, int16, int, int32, int64:
+		i, _ = ei.N(x).Int64()
+		negative = i < 0
+		u = uint64(i)
+	case uint8, uint16, uint, uint32, uint64:
+		u, _ = ei.N(x).Uint64()
+	case float32, float64:
+		f, _ := ei.N(x).Float64()
+		switch {
+		case math.IsNaN(f):
+			return nil, fmt.Errorf("NaN can't be stored in field '%s'", field.name)
+		case f < 0:
+			negative = true
+			i = math.MinInt64
+			if f > math.MinInt64 {
+				i = int64(f)
+			}
+		case f >= 1<<64:
+			u = math.MaxUint64
+		default:
+			u = uint64(f)
+		}
+	default:
+		return v, nil
+	}
+	oe := &buffer.OverflowError{Value: v, Size: field.size, Signed: isSignedField(field), Encoding: field.encoding}
+	switch {
+	case negative && i < oe.Min():
+		if field.overflow == buffer.OverflowSaturate {
+			return oe.Min(), nil
+		}
+	case !negative && u > oe.Max():
+		if field.overflow == buffer.OverflowSaturate {
+			return oe.Max(), nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("field '%s': %w", field.name, oe)
+}
@@ -0,0 +1,220 @@
+package linecode
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// Line codes map every data bit to line levels, a set bit being a high
+// level. Manchester codes use two half-bit levels per data bit. Decoders
+// return the positions (in the coded input) of the code violations found
+// instead of failing, so damaged streams can still be inspected.
+
+// ManchesterConvention selects the level pairs used by Manchester coding.
+type ManchesterConvention int
+
+const (
+	// IEEE 802.3: 0 is high-low and 1 is low-high.
+	IEEE ManchesterConvention = iota
+	// Thomas (G.E. Thomas): 0 is low-high and 1 is high-low.
+	Thomas
+)
+
+// NRZIMode selects which data value toggles the line level in NRZI.
+type NRZIMode int
+
+const (
+	// NRZIMark toggles the level on ones (NRZ-M).
+	NRZIMark NRZIMode = iota
+	// NRZISpace toggles the level on zeros (NRZ-S, as in USB).
+	NRZISpace
+)
+
+func ManchesterEncode(input *buffer.Buffer, conv ManchesterConvention) (out *buffer.Buffer, err error) {
+	if err = conv.check(); err != nil {
+		return nil, err
+	}
+	out, w := newOutput(input, input.GetBitSize()*2)
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, err
+		}
+		// IEEE sends the complement first
+		first := v != (conv == IEEE)
+		if err = writePair(w, first, !first); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ManchesterDecode decodes pairs of half bits. Pairs without a transition
+// are reported as violations and decoded from their second half (IEEE) or
+// first half (Thomas).
+func ManchesterDecode(input *buffer.Buffer, conv ManchesterConvention) (out *buffer.Buffer, violations []int, err error) {
+	if err = conv.check(); err != nil {
+		return nil, nil, err
+	}
+	if err = checkPairs(input); err != nil {
+		return nil, nil, err
+	}
+	out, w := newOutput(input, input.GetBitSize()/2)
+	for pos := 0; pos < input.GetBitSize(); pos += 2 {
+		first, second, err := readPair(input, pos)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == second {
+			violations = append(violations, pos)
+		}
+		v := first
+		if conv == IEEE {
+			v = second
+		}
+		if err = w.WriteBool(v); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, violations, nil
+}
+
+// DiffManchesterEncode encodes input using differential Manchester: every bit
+// has a transition in the middle, and zeros also have one at the start.
+// initial is the line level before the first bit.
+func DiffManchesterEncode(input *buffer.Buffer, initial bool) (out *buffer.Buffer, err error) {
+	out, w := newOutput(input, input.GetBitSize()*2)
+	level := initial
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, err
+		}
+		if !v {
+			level = !level
+		}
+		if err = writePair(w, level, !level); err != nil {
+			return nil, err
+		}
+		level = !level
+	}
+	return out, nil
+}
+
+// DiffManchesterDecode is the inverse of DiffManchesterEncode. Pairs without
+// a transition in the middle are reported as violations; their value is
+// still taken from the transition at the start.
+func DiffManchesterDecode(input *buffer.Buffer, initial bool) (out *buffer.Buffer, violations []int, err error) {
+	if err = checkPairs(input); err != nil {
+		return nil, nil, err
+	}
+	out, w := newOutput(input, input.GetBitSize()/2)
+	level := initial
+	for pos := 0; pos < input.GetBitSize(); pos += 2 {
+		first, second, err := readPair(input, pos)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == second {
+			violations = append(violations, pos)
+		}
+		if err = w.WriteBool(first == level); err != nil {
+			return nil, nil, err
+		}
+		level = second
+	}
+	return out, violations, nil
+}
+
+// NRZIEncode encodes input using NRZI. initial is the line level before the
+// first bit.
+func NRZIEncode(input *buffer.Buffer, mode NRZIMode, initial bool) (out *buffer.Buffer, err error) {
+	if err = mode.check(); err != nil {
+		return nil, err
+	}
+	out, w := newOutput(input, input.GetBitSize())
+	level := initial
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, err
+		}
+		if v == (mode == NRZIMark) {
+			level = !level
+		}
+		if err = w.WriteBool(level); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// NRZIDecode is the inverse of NRZIEncode. Every level sequence is valid
+// NRZI, so there are no violations to report.
+func NRZIDecode(input *buffer.Buffer, mode NRZIMode, initial bool) (out *buffer.Buffer, err error) {
+	if err = mode.check(); err != nil {
+		return nil, err
+	}
+	out, w := newOutput(input, input.GetBitSize())
+	level := initial
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, err
+		}
+		toggled := v != level
+		if err = w.WriteBool(toggled == (mode == NRZIMark)); err != nil {
+			return nil, err
+		}
+		level = v
+	}
+	return out, nil
+}
+
+func (c ManchesterConvention) check() error {
+	if c != IEEE && c != Thomas {
+		return fmt.Errorf("invalid Manchester convention (%d)", int(c))
+	}
+	return nil
+}
+
+func (m NRZIMode) check() error {
+	if m != NRZIMark && m != NRZISpace {
+		return fmt.Errorf("invalid NRZI mode (%d)", int(m))
+	}
+	return nil
+}
+
+// newOutput returns an output buffer with the bit order of input and a writer
+// placed at its start.
+func newOutput(input *buffer.Buffer, numBits int) (*buffer.Buffer, *buffer.BitWriter) {
+	out := &buffer.Buffer{}
+	out.InitWithBitOrder(numBits, input.GetBitOrder())
+	w := buffer.NewBitWriter(out)
+	w.SeekBit(0, io.SeekStart)
+	return out, w
+}
+
+func checkPairs(input *buffer.Buffer) error {
+	if input.GetBitSize()%2 != 0 {
+		return fmt.Errorf("odd number of half bits (%d)", input.GetBitSize())
+	}
+	return nil
+}
+
+func writePair(w *buffer.BitWriter, first, second bool) error {
+	if err := w.WriteBool(first); err != nil {
+		return err
+	}
+	return w.WriteBool(second)
+}
+
+func readPair(input *buffer.Buffer, pos int) (first, second bool, err error) {
+	if first, err = input.GetBit(pos); err != nil {
+		return
+	}
+	second, err = input.GetBit(pos + 1)
+	return
+}
@@ -0,0 +1,129 @@
+package linecode
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/nayarsystems/buffer/buffer"
+	"github.com/stretchr/testify/require"
+)
+
+func fromString(s string) *buffer.Buffer {
+	s = strings.ReplaceAll(s, " ", "")
+	b := &buffer.Buffer{}
+	b.Init(len(s))
+	for i, c := range s {
+		b.SetBit(i, c == '1')
+	}
+	return b
+}
+
+func toString(b *buffer.Buffer) string {
+	var sb strings.Builder
+	for i := 0; i < b.GetBitSize(); i++ {
+		v, _ := b.GetBit(i)
+		if v {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+func Test_Manchester(t *testing.T) {
+	out, err := ManchesterEncode(fromString("0110"), IEEE)
+	require.Nil(t, err)
+	require.Equal(t, "10010110", toString(out))
+	out, err = ManchesterEncode(fromString("0110"), Thomas)
+	require.Nil(t, err)
+	require.Equal(t, "01101001", toString(out))
+
+	dec, violations, err := ManchesterDecode(fromString("10 01 11 10 00"), IEEE)
+	require.Nil(t, err)
+	require.Equal(t, "01100", toString(dec))
+	require.Equal(t, []int{4, 8}, violations)
+
+	dec, violations, err = ManchesterDecode(fromString("01 10 01"), Thomas)
+	require.Nil(t, err)
+	require.Equal(t, "010", toString(dec))
+	require.Nil(t, violations)
+
+	_, _, err = ManchesterDecode(fromString("010"), IEEE)
+	require.NotNil(t, err)
+	_, err = ManchesterEncode(fromString("0"), ManchesterConvention(2))
+	require.NotNil(t, err)
+}
+
+func Test_DiffManchester(t *testing.T) {
+	out, err := DiffManchesterEncode(fromString("0011"), false)
+	require.Nil(t, err)
+	require.Equal(t, "10100110", toString(out))
+
+	dec, violations, err := DiffManchesterDecode(out, false)
+	require.Nil(t, err)
+	require.Equal(t, "0011", toString(dec))
+	require.Nil(t, violations)
+
+	dec, violations, err = DiffManchesterDecode(fromString("10 11 01"), false)
+	require.Nil(t, err)
+	require.Equal(t, []int{2}, violations)
+	require.Equal(t, "000", toString(dec))
+}
+
+func Test_NRZI(t *testing.T) {
+	out, err := NRZIEncode(fromString("1011 0001"), NRZIMark, false)
+	require.Nil(t, err)
+	require.Equal(t, "11011110", toString(out))
+	out, err = NRZIEncode(fromString("1011 0001"), NRZISpace, true)
+	require.Nil(t, err)
+	require.Equal(t, "10001011", toString(out))
+	_, err = NRZIDecode(out, NRZIMode(5), true)
+	require.NotNil(t, err)
+}
+
+func Test_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(20))
+	for i := 0; i < 100; i++ {
+		raw := make([]byte, 1+rnd.Intn(16))
+		rnd.Read(raw)
+		order := buffer.MSBFirst
+		if i%2 == 0 {
+			order = buffer.LSBFirst
+		}
+		in := &buffer.Buffer{}
+		require.Nil(t, in.InitFromRawBufferNWithBitOrder(raw, len(raw)*8-rnd.Intn(8), order))
+		initial := rnd.Intn(2) == 0
+
+		for _, conv := range []ManchesterConvention{IEEE, Thomas} {
+			coded, err := ManchesterEncode(in, conv)
+			require.Nil(t, err)
+			require.Equal(t, order, coded.GetBitOrder())
+			dec, violations, err := ManchesterDecode(coded, conv)
+			require.Nil(t, err)
+			require.Nil(t, violations)
+			require.Equal(t, toString(in), toString(dec))
+		}
+
+		coded, err := DiffManchesterEncode(in, initial)
+		require.Nil(t, err)
+		dec, violations, err := DiffManchesterDecode(coded, initial)
+		require.Nil(t, err)
+		require.Nil(t, violations)
+		require.Equal(t, toString(in), toString(dec))
+		// differential Manchester doesn't depend on the polarity
+		coded.Not()
+		dec, _, err = DiffManchesterDecode(coded, !initial)
+		require.Nil(t, err)
+		require.Equal(t, toString(in), toString(dec))
+
+		for _, mode := range []NRZIMode{NRZIMark, NRZISpace} {
+			coded, err := NRZIEncode(in, mode, initial)
+			require.Nil(t, err)
+			dec, err := NRZIDecode(coded, mode, initial)
+			require.Nil(t, err)
+			require.Equal(t, toString(in), toString(dec))
+		}
+	}
+}
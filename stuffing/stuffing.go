@@ -0,0 +1,175 @@
+package stuffing
+
+import (
+	"fmt"
+
+	"github.com/nayarsystems/buffer/buffer"
+)
+
+// Rule describes a bit stuffing scheme: after Run consecutive equal bits a
+// complementary bit is inserted. When OnesOnly is set only runs of ones are
+// stuffed. Stuff bits take part in the following run.
+type Rule struct {
+	Run      int
+	OnesOnly bool
+}
+
+var (
+	// CAN inserts a complementary bit after five equal bits.
+	CAN = Rule{Run: 5}
+	// HDLC inserts a zero after five ones.
+	HDLC = Rule{Run: 5, OnesOnly: true}
+)
+
+// Error reports a stuffing violation found by Destuff.
+type Error struct {
+	// Pos is the position of the offending bit in the stuffed input.
+	Pos int
+	// Missing is set when the input ends where a stuff bit was expected.
+	Missing bool
+}
+
+func (e *Error) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("missing stuff bit at %d", e.Pos)
+	}
+	return fmt.Sprintf("stuffing error at bit %d", e.Pos)
+}
+
+// Map relates the positions of a stuffed stream and its unstuffed data.
+type Map struct {
+	// Data holds the stuffed position of every unstuffed bit.
+	Data []int
+	// Stuff holds the positions of the stuff bits in the stuffed stream.
+	Stuff []int
+}
+
+// ToStuffed returns the stuffed position of the unstuffed bit idx.
+func (m *Map) ToStuffed(idx int) (int, error) {
+	if idx < 0 || idx >= len(m.Data) {
+		return 0, fmt.Errorf("index out of range (%d)", idx)
+	}
+	return m.Data[idx], nil
+}
+
+// ToUnstuffed returns the unstuffed position of the stuffed bit pos, or -1 if
+// it is a stuff bit.
+func (m *Map) ToUnstuffed(pos int) (int, error) {
+	size := len(m.Data) + len(m.Stuff)
+	if pos < 0 || pos >= size {
+		return 0, fmt.Errorf("index out of range (%d)", pos)
+	}
+	// the number of stuff bits before pos
+	lo, hi := 0, len(m.Stuff)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if m.Stuff[mid] < pos {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(m.Stuff) && m.Stuff[lo] == pos {
+		return -1, nil
+	}
+	return pos - lo, nil
+}
+
+// Stuff returns input with the stuff bits of rule inserted.
+func Stuff(input *buffer.Buffer, rule Rule) (out *buffer.Buffer, m *Map, err error) {
+	if err = rule.check(); err != nil {
+		return nil, nil, err
+	}
+	out = &buffer.Buffer{}
+	out.InitWithBitOrder(0, input.GetBitOrder())
+	w := buffer.NewBitWriter(out)
+	m = &Map{Data: make([]int, 0, input.GetBitSize())}
+	r := run{rule: rule}
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		m.Data = append(m.Data, w.Pos())
+		if err = w.WriteBool(v); err != nil {
+			return nil, nil, err
+		}
+		if r.add(v) {
+			m.Stuff = append(m.Stuff, w.Pos())
+			if err = w.WriteBool(!v); err != nil {
+				return nil, nil, err
+			}
+			r.add(!v)
+		}
+	}
+	return out, m, nil
+}
+
+// Destuff removes the stuff bits of rule from input. A stuff bit with the
+// wrong value, or missing at the end of the input, is reported as an *Error.
+func Destuff(input *buffer.Buffer, rule Rule) (out *buffer.Buffer, m *Map, err error) {
+	if err = rule.check(); err != nil {
+		return nil, nil, err
+	}
+	out = &buffer.Buffer{}
+	out.InitWithBitOrder(0, input.GetBitOrder())
+	w := buffer.NewBitWriter(out)
+	m = &Map{Data: make([]int, 0, input.GetBitSize())}
+	r := run{rule: rule}
+	stuffPending := false
+	var last bool
+	for i := 0; i < input.GetBitSize(); i++ {
+		v, err := input.GetBit(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if stuffPending {
+			if v == last {
+				return nil, nil, &Error{Pos: i}
+			}
+			m.Stuff = append(m.Stuff, i)
+			r.add(v)
+			stuffPending = false
+			continue
+		}
+		m.Data = append(m.Data, i)
+		if err = w.WriteBool(v); err != nil {
+			return nil, nil, err
+		}
+		stuffPending = r.add(v)
+		last = v
+	}
+	if stuffPending {
+		return nil, nil, &Error{Pos: input.GetBitSize(), Missing: true}
+	}
+	return out, m, nil
+}
+
+func (r Rule) check() error {
+	if r.Run <= 1 {
+		return fmt.Errorf("invalid run length (%d). Must be > 1", r.Run)
+	}
+	return nil
+}
+
+// run counts the bits of the current run.
+type run struct {
+	rule  Rule
+	value bool
+	count int
+}
+
+// add appends v to the run and reports whether it must be followed by a stuff
+// bit.
+func (r *run) add(v bool) bool {
+	if r.count > 0 && v == r.value {
+		r.count++
+	} else {
+		r.value = v
+		r.count = 1
+	}
+	if r.rule.OnesOnly && !v {
+		return false
+	}
+	return r.count == r.rule.Run
+}
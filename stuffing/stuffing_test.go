@@ -0,0 +1,142 @@
+package stuffing
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/nayarsystems/buffer/buffer"
+	"github.com/stretchr/testify/require"
+)
+
+func fromString(s string) *buffer.Buffer {
+	s = strings.ReplaceAll(s, " ", "")
+	b := &buffer.Buffer{}
+	b.Init(len(s))
+	for i, c := range s {
+		b.SetBit(i, c == '1')
+	}
+	return b
+}
+
+func toString(b *buffer.Buffer) string {
+	var sb strings.Builder
+	for i := 0; i < b.GetBitSize(); i++ {
+		v, _ := b.GetBit(i)
+		if v {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+func Test_Stuff_KnownStreams(t *testing.T) {
+	cases := []struct {
+		rule    Rule
+		in, out string
+		stuff   []int
+	}{
+		{HDLC, "01111110", "011111010", []int{6}},
+		{HDLC, "11111 11111 00000", "11111 0 11111 0 00000", []int{5, 11}},
+		{HDLC, "11111", "11111 0", []int{5}},
+		{CAN, "00000 1111", "00000 1 1111 0", []int{5, 10}},
+		{CAN, "0000 1111 000", "0000 1111 000", nil},
+		{CAN, "11111 11111", "11111 0 11111 0", []int{5, 11}},
+	}
+	for _, c := range cases {
+		out, m, err := Stuff(fromString(c.in), c.rule)
+		require.Nil(t, err)
+		require.Equal(t, strings.ReplaceAll(c.out, " ", ""), toString(out), c.in)
+		require.Equal(t, c.stuff, m.Stuff, c.in)
+
+		back, m2, err := Destuff(out, c.rule)
+		require.Nil(t, err)
+		require.Equal(t, strings.ReplaceAll(c.in, " ", ""), toString(back))
+		require.Equal(t, m, m2)
+	}
+}
+
+func Test_Stuff_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(19))
+	for i := 0; i < 200; i++ {
+		rule := CAN
+		if i%2 == 0 {
+			rule = HDLC
+		}
+		order := buffer.MSBFirst
+		if i%4 < 2 {
+			order = buffer.LSBFirst
+		}
+		// long runs are common in real data
+		raw := make([]byte, 1+rnd.Intn(20))
+		for j := range raw {
+			raw[j] = []byte{0x00, 0xff, byte(rnd.Intn(256))}[rnd.Intn(3)]
+		}
+		in := &buffer.Buffer{}
+		require.Nil(t, in.InitFromRawBufferNWithBitOrder(raw, len(raw)*8-rnd.Intn(8), order))
+
+		out, m, err := Stuff(in, rule)
+		require.Nil(t, err)
+		require.Equal(t, order, out.GetBitOrder())
+		require.Equal(t, in.GetBitSize()+len(m.Stuff), out.GetBitSize())
+		for j := 0; j < in.GetBitSize(); j++ {
+			pos, err := m.ToStuffed(j)
+			require.Nil(t, err)
+			a, _ := in.GetBit(j)
+			b, _ := out.GetBit(pos)
+			require.Equal(t, a, b)
+			back, err := m.ToUnstuffed(pos)
+			require.Nil(t, err)
+			require.Equal(t, j, back)
+		}
+		for _, pos := range m.Stuff {
+			back, err := m.ToUnstuffed(pos)
+			require.Nil(t, err)
+			require.Equal(t, -1, back)
+		}
+
+		back, _, err := Destuff(out, rule)
+		require.Nil(t, err)
+		require.Equal(t, in.GetBitSize(), back.GetBitSize())
+		require.Equal(t, toString(in), toString(back))
+	}
+}
+
+func Test_Destuff_Errors(t *testing.T) {
+	cases := []struct {
+		rule    Rule
+		in      string
+		pos     int
+		missing bool
+	}{
+		{HDLC, "0111111", 6, false},
+		{HDLC, "11111 0 111111", 11, false},
+		{HDLC, "011111", 6, true},
+		{CAN, "000000", 5, false},
+		{CAN, "11111 0 00000", 10, false},
+		{CAN, "00000", 5, true},
+	}
+	for _, c := range cases {
+		_, _, err := Destuff(fromString(c.in), c.rule)
+		var se *Error
+		require.True(t, errors.As(err, &se), c.in)
+		require.Equal(t, c.pos, se.Pos, c.in)
+		require.Equal(t, c.missing, se.Missing, c.in)
+	}
+	_, _, err := Stuff(fromString("0"), Rule{Run: 1})
+	require.NotNil(t, err)
+}
+
+func Test_Map_OutOfRange(t *testing.T) {
+	_, m, err := Stuff(fromString("11111"), HDLC)
+	require.Nil(t, err)
+	_, err = m.ToStuffed(5)
+	require.NotNil(t, err)
+	_, err = m.ToUnstuffed(6)
+	require.NotNil(t, err)
+	_, err = m.ToUnstuffed(-1)
+	require.NotNil(t, err)
+}
@@ -2,6 +2,7 @@ package vars
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"sync"
 
@@ -31,6 +32,8 @@ func (r *Var) GetCopy() *Var {
 		dstValue := make([]byte, len(v))
 		copy(dstValue, v)
 		vcopy.Value = dstValue
+	case *big.Int:
+		vcopy.Value = new(big.Int).Set(v)
 	default:
 		vcopy.Value = v
 	}
@@ -97,7 +100,7 @@ func (r *VarsBank) UnsafeSet(varName string, newValue interface{}) (err error) {
 	if setValue, err = getSetValue(oldValue, newValue); err != nil {
 		return fmt.Errorf("can't update %s (%T) by a value of different type (%T): %s", varName, oldValue, newValue, err.Error())
 	}
-	if !reg.IsSet || !reflect.DeepEqual(oldValue, newValue) {
+	if !reg.IsSet || !sameValue(oldValue, newValue) {
 		reg.Value = setValue
 		reg.IsSet = true
 		if reg.VarUpdatedCb != nil {
@@ -186,7 +189,7 @@ func (r *VarsBank) Same(varName string, newValue interface{}) (bool, error) {
 	if setValue, err = getSetValue(curretValue, newValue); err != nil {
 		return false, nil
 	}
-	return reflect.DeepEqual(curretValue, setValue), nil
+	return sameValue(curretValue, setValue), nil
 }
 
 func (r *VarsBank) UnsafeGet(varName string) (interface{}, error) {
@@ -383,6 +386,12 @@ func (r *VarsBank) UnsafeGetTo(varName string, dst interface{}) error {
 			return err
 		}
 		*v = setValue
+	case *big.Int:
+		setValue, err := toBigInt(rawValue)
+		if err != nil {
+			return err
+		}
+		v.Set(setValue)
 	default:
 		return fmt.Errorf("type '%v' not supported", v)
 	}
@@ -407,7 +416,58 @@ func sameType(a, b interface{}) bool {
 	return reflect.TypeOf(a) == reflect.TypeOf(b)
 }
 
+// sameValue compares values as reflect.DeepEqual does, except for *big.Int
+// values, which are compared by value.
+func sameValue(a, b interface{}) bool {
+	if x, ok := a.(*big.Int); ok {
+		if y, ok := b.(*big.Int); ok && x != nil && y != nil {
+			return x.Cmp(y) == 0
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toBigInt returns a new *big.Int holding the integer v.
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch x := v.(type) {
+	case *big.Int:
+		if x == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		return new(big.Int).Set(x), nil
+	case uint8, uint16, uint, uint32, uint64:
+		u, err := ei.N(x).Uint64()
+		return new(big.Int).SetUint64(u), err
+	case int8, int16, int, int32, int64:
+		i, err := ei.N(x).Int64()
+		return big.NewInt(i), err
+	case string:
+		// base prefixes (0x, 0b, 0o) are accepted
+		b, ok := new(big.Int).SetString(x, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer \"%s\"", x)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("conversion not possible")
+	}
+}
+
 func getSetValue(oldValue interface{}, newValue interface{}) (setValue interface{}, err error) {
+	if _, ok := oldValue.(*big.Int); ok {
+		// always copied, so the caller can't modify the stored value
+		return toBigInt(newValue)
+	}
+	if b, ok := newValue.(*big.Int); ok && b != nil && !sameType(oldValue, newValue) {
+		switch {
+		case b.IsInt64():
+			newValue = b.Int64()
+		case b.IsUint64():
+			newValue = b.Uint64()
+		default:
+			return nil, fmt.Errorf("value %v out of range", b)
+		}
+	}
 	if sameType(oldValue, newValue) {
 		setValue = newValue
 	} else {
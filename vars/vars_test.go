@@ -1,6 +1,7 @@
 package vars
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,3 +23,39 @@ func Test_String(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, v, "Hello!")
 }
+
+func Test_BigInt(t *testing.T) {
+	vb := CreateVarsBank()
+	varname := "V_BIG"
+	vb.InitVar(varname, new(big.Int), nil)
+
+	in, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.Nil(t, vb.Set(varname, in))
+	in.SetInt64(0)
+	v, err := vb.Get(varname)
+	require.Nil(t, err)
+	require.Equal(t, "123456789012345678901234567890", v.(*big.Int).String())
+
+	out := new(big.Int)
+	require.Nil(t, vb.GetTo(varname, out))
+	require.Equal(t, "123456789012345678901234567890", out.String())
+
+	same, err := vb.Same(varname, "0x18ee90ff6c373e0ee4e3f0ad2")
+	require.Nil(t, err)
+	require.True(t, same)
+
+	cp := vb.GetCopy()
+	require.Nil(t, vb.Set(varname, -5))
+	v, err = cp.Get(varname)
+	require.Nil(t, err)
+	require.Equal(t, "123456789012345678901234567890", v.(*big.Int).String())
+	require.NotNil(t, vb.Set(varname, 1.5))
+
+	// big values can be set to other integers when they fit
+	vb.InitVar("V_INT", int32(0), nil)
+	require.Nil(t, vb.Set("V_INT", big.NewInt(-7)))
+	v, err = vb.Get("V_INT")
+	require.Nil(t, err)
+	require.Equal(t, int32(-7), v)
+	require.NotNil(t, vb.Set("V_INT", in.Lsh(big.NewInt(1), 70)))
+}